@@ -0,0 +1,351 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// report reports the misaligned values along with the changes that would
+// align them. Every entry holds the misalignments of a single value on the
+// platforms where it breaks. Reordering the fields of the struct is offered as
+// a suggested fix when it's safe.
+func report(pass *analysis.Pass, misaligned [][]misalignment, atomicFields map[*types.Var]bool) {
+	for _, broken := range misaligned {
+		m := broken[0]
+
+		var message strings.Builder
+		fmt.Fprintf(&message, "address of non 64-bit aligned field passed to atomic on %s", describeOffsets(broken))
+
+		if m.field != nil {
+			fmt.Fprintf(&message, "\n\tconsider declaring %s as %s, which is always 64-bit aligned",
+				m.field.Name(), typedAtomic(m.typ))
+		} else {
+			fmt.Fprintf(&message, "\n\tconsider storing it as %s, which is always 64-bit aligned",
+				typedAtomic(m.typ))
+		}
+
+		var fixes []analysis.SuggestedFix
+		if order, ok := reorderFields(broken, atomicFields); ok {
+			names := make([]string, len(order))
+			for i, field := range order {
+				names[i] = field.Name()
+			}
+			fmt.Fprintf(&message, "\n\tor reorder the fields of %s as: %s",
+				structName(pass, m), strings.Join(names, ", "))
+
+			if m.named != nil {
+				fix, err := reorderFix(pass, m.named, order)
+				if err != nil {
+					fmt.Fprintf(&message, " (not fixed automatically: %v)", err)
+				} else {
+					fixes = append(fixes, fix)
+				}
+			}
+		}
+
+		pass.Report(analysis.Diagnostic{
+			Pos:            m.pos,
+			Category:       "alignment",
+			Message:        message.String(),
+			SuggestedFixes: fixes,
+		})
+	}
+}
+
+// describeOffsets lists the broken architectures grouped by their offsets,
+// for example "386, arm (offset: 12)".
+func describeOffsets(broken []misalignment) string {
+	var groups []string
+	archs := make(map[string][]string)
+	for _, m := range broken {
+		offset := fmt.Sprint(m.offset)
+		if m.stride != 0 {
+			offset += fmt.Sprintf(" + %d*i", m.stride)
+		}
+		if _, ok := archs[offset]; !ok {
+			groups = append(groups, offset)
+		}
+		archs[offset] = append(archs[offset], m.platform.arch)
+	}
+
+	parts := make([]string, len(groups))
+	for i, offset := range groups {
+		parts[i] = fmt.Sprintf("%s (offset: %s)", strings.Join(archs[offset], ", "), offset)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// reorderFields returns the fields of the struct containing the misaligned
+// field with all of its atomically accessed fields moved to the front. The
+// first word of a struct is 64-bit aligned whenever the struct itself is, so
+// it returns false when the struct is at a misaligned offset in its parent on
+// any of the broken platforms and reordering would not help.
+func reorderFields(broken []misalignment, atomicFields map[*types.Var]bool) ([]*types.Var, bool) {
+	if broken[0].parent == nil {
+		return nil, false
+	}
+
+	var order, rest []*types.Var
+	for _, field := range structFields(broken[0].parent) {
+		if atomicFields[field] {
+			order = append(order, field)
+		} else {
+			rest = append(rest, field)
+		}
+	}
+	order = append(order, rest...)
+
+	for _, m := range broken {
+		if m.stride&7 != 0 {
+			return nil, false
+		}
+		offsets := m.platform.sizes.Offsetsof(order)
+		for i, field := range order {
+			if field == m.field && (m.parentOffset+offsets[i])&7 != 0 {
+				return nil, false
+			}
+		}
+	}
+	return order, true
+}
+
+// typedAtomic returns the sync/atomic type replacing a raw 64-bit integer.
+func typedAtomic(in types.Type) string {
+	if basic, ok := in.Underlying().(*types.Basic); ok && basic.Kind() == types.Uint64 {
+		return "atomic.Uint64"
+	}
+	return "atomic.Int64"
+}
+
+// structName returns a printable name for the struct containing the
+// misaligned field.
+func structName(pass *analysis.Pass, m misalignment) string {
+	if m.named == nil {
+		return "the enclosing struct"
+	}
+	return types.TypeString(m.named, types.RelativeTo(pass.Pkg))
+}
+
+// reorderFix returns the fix that reorders the fields in the declaration of
+// named. Since the field order is part of the API, it refuses to reorder
+// exported structs, which other packages may build with unkeyed composite
+// literals, as well as structs built with them in the package.
+func reorderFix(pass *analysis.Pass, named *types.Named, order []*types.Var) (analysis.SuggestedFix, error) {
+	var fix analysis.SuggestedFix
+
+	obj := named.Obj()
+	if obj.Pkg() != pass.Pkg {
+		return fix, fmt.Errorf("declared in package %s", obj.Pkg().Path())
+	}
+	if obj.Exported() {
+		return fix, fmt.Errorf("%s is exported", obj.Name())
+	}
+
+	// find the struct declaration
+	var file *ast.File
+	var decl *ast.StructType
+	for _, f := range pass.Files {
+		ast.Inspect(f, func(node ast.Node) bool {
+			spec, ok := node.(*ast.TypeSpec)
+			if ok && pass.TypesInfo.Defs[spec.Name] == obj {
+				if st, ok := spec.Type.(*ast.StructType); ok {
+					file, decl = f, st
+				}
+			}
+			return decl == nil
+		})
+	}
+	if decl == nil {
+		return fix, fmt.Errorf("struct declaration not found")
+	}
+
+	if pos, ok := findUnkeyedLiteral(pass, named); ok {
+		return fix, fmt.Errorf("unkeyed composite literal at %s", pos)
+	}
+	if pos, ok := findUnkeyedTestLiteral(pass, named); ok {
+		return fix, fmt.Errorf("unkeyed composite literal at %s", pos)
+	}
+
+	// match every field with its declaration
+	var declared []*ast.Field
+	for _, field := range decl.Fields.List {
+		if len(field.Names) > 1 {
+			return fix, fmt.Errorf("fields %s are declared together", field.Names[0].Name)
+		}
+		declared = append(declared, field)
+	}
+	fields := structFields(named.Underlying().(*types.Struct))
+	if len(fields) != len(declared) {
+		return fix, fmt.Errorf("unexpected number of fields")
+	}
+
+	tokFile := pass.Fset.File(file.Pos())
+	src, err := pass.ReadFile(tokFile.Name())
+	if err != nil {
+		return fix, err
+	}
+
+	// find the source of every field including its comments
+	spans := make(map[*types.Var][2]token.Pos, len(fields))
+	owned := make(map[*ast.CommentGroup]bool)
+	for i, field := range declared {
+		start, end := field.Pos(), field.End()
+		if field.Doc != nil {
+			start = field.Doc.Pos()
+			owned[field.Doc] = true
+		}
+		if field.Comment != nil {
+			end = field.Comment.End()
+			owned[field.Comment] = true
+		}
+		spans[fields[i]] = [2]token.Pos{start, end}
+	}
+
+	first, last := spans[fields[0]][0], spans[fields[len(fields)-1]][1]
+	for _, group := range file.Comments {
+		pos := group.Pos()
+		if owned[group] || pos < first || pos >= last {
+			continue
+		}
+		inside := false
+		for _, span := range spans {
+			inside = inside || (span[0] <= pos && pos < span[1])
+		}
+		if !inside {
+			return fix, fmt.Errorf("comment between fields at %s", pass.Fset.Position(pos))
+		}
+	}
+
+	var text bytes.Buffer
+	for i, field := range order {
+		if i > 0 {
+			text.WriteByte('\n')
+		}
+		span := spans[field]
+		text.Write(src[tokFile.Offset(span[0]):tokFile.Offset(span[1])])
+	}
+
+	return analysis.SuggestedFix{
+		Message:   "Reorder the fields of " + obj.Name(),
+		TextEdits: []analysis.TextEdit{{Pos: first, End: last, NewText: text.Bytes()}},
+	}, nil
+}
+
+// findUnkeyedLiteral returns the position of a composite literal of named
+// that does not use field names.
+func findUnkeyedLiteral(pass *analysis.Pass, named *types.Named) (pos string, found bool) {
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			lit, ok := node.(*ast.CompositeLit)
+			if !ok || found || len(lit.Elts) == 0 {
+				return !found
+			}
+			if _, keyed := lit.Elts[0].(*ast.KeyValueExpr); keyed {
+				return true
+			}
+			if typ, ok := pass.TypesInfo.TypeOf(lit).(*types.Named); ok && typ.Obj() == named.Obj() {
+				pos, found = pass.Fset.Position(lit.Pos()).String(), true
+			}
+			return !found
+		})
+	}
+	return pos, found
+}
+
+// findUnkeyedTestLiteral is like findUnkeyedLiteral for the test files of the
+// package, which are not part of the pass when the package is analyzed without
+// its tests. The files are only parsed, so a literal is identified by the name
+// of its type, including literals with the type elided in a slice, array or
+// map of named.
+func findUnkeyedTestLiteral(pass *analysis.Pass, named *types.Named) (pos string, found bool) {
+	if len(pass.Files) == 0 {
+		return "", false
+	}
+
+	analyzed := make(map[string]bool)
+	for _, file := range pass.Files {
+		analyzed[pass.Fset.File(file.Pos()).Name()] = true
+	}
+	dir := filepath.Dir(pass.Fset.File(pass.Files[0].Pos()).Name())
+	filenames, err := filepath.Glob(filepath.Join(dir, "*_test.go"))
+	if err != nil {
+		return "", false
+	}
+
+	name := named.Obj().Name()
+	fset := token.NewFileSet()
+	for _, filename := range filenames {
+		if analyzed[filename] {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filename, nil, parser.SkipObjectResolution)
+		if err != nil || file.Name.Name != pass.Pkg.Name() {
+			// external tests can't refer to the unexported types that are fixed
+			continue
+		}
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			lit, ok := node.(*ast.CompositeLit)
+			if !ok || found {
+				return !found
+			}
+			var elem ast.Expr
+			switch typ := lit.Type.(type) {
+			case *ast.ArrayType:
+				elem = typ.Elt
+			case *ast.MapType:
+				elem = typ.Value
+			}
+			if namesType(lit.Type, name) && unkeyed(lit) {
+				pos, found = fset.Position(lit.Pos()).String(), true
+				return false
+			}
+			if elem != nil && namesType(elem, name) {
+				for _, elt := range lit.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok {
+						elt = kv.Value
+					}
+					if elided, ok := ast.Unparen(elt).(*ast.CompositeLit); ok && elided.Type == nil && unkeyed(elided) {
+						pos, found = fset.Position(elided.Pos()).String(), true
+						return false
+					}
+				}
+			}
+			return true
+		})
+		if found {
+			return pos, found
+		}
+	}
+	return "", false
+}
+
+// namesType reports whether expr refers to the type or pointer type called name.
+func namesType(expr ast.Expr, name string) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	ident, ok := ast.Unparen(expr).(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+// unkeyed reports whether the composite literal lists its values without
+// field names.
+func unkeyed(lit *ast.CompositeLit) bool {
+	if len(lit.Elts) == 0 {
+		return false
+	}
+	_, keyed := lit.Elts[0].(*ast.KeyValueExpr)
+	return !keyed
+}
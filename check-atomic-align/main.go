@@ -6,63 +6,82 @@ package main
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
 	"go/types"
-	"log"
-	"os"
+	"strings"
 
-	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/singlechecker"
 )
 
-// exit holds on to the eventual exit code.
-var exit int
+// Analyzer checks that the values passed to 64-bit atomic functions are 64-bit
+// aligned on 32-bit platforms and that atomically accessed fields are not
+// accessed without sync/atomic elsewhere.
+var Analyzer = &analysis.Analyzer{
+	Name: "atomicalign",
+	Doc:  "check that values accessed with 64-bit atomics are 64-bit aligned on 32-bit platforms",
+	Run:  run,
+}
 
-func main() {
-	// load imports of requested packages to filter out any
-	// that do not import sync/atomic
-	pkgs, err := packages.Load(&packages.Config{
-		Mode: packages.NeedImports,
-	}, os.Args[1:]...)
-	if err != nil {
-		log.Fatal(err)
-	}
+var archs string
 
-	var imports []string
-	for _, pkg := range pkgs {
-		if _, ok := pkg.Imports["sync/atomic"]; ok {
-			imports = append(imports, pkg.PkgPath)
-		}
+func init() {
+	Analyzer.Flags.StringVar(&archs, "archs", "386,arm,mips", "comma separated list of architectures to check the alignment on")
+}
+
+func main() { singlechecker.Main(Analyzer) }
+
+func run(pass *analysis.Pass) (any, error) {
+	if !importsAtomic(pass.Pkg) {
+		return nil, nil
 	}
 
-	// load up the requested packages
-	pkgs, err = packages.Load(&packages.Config{
-		Mode: 0 |
-			packages.NeedTypes |
-			packages.NeedTypesInfo |
-			packages.NeedTypesSizes |
-			packages.NeedSyntax |
-			packages.NeedImports |
-			packages.NeedName,
-	}, imports...)
+	platforms, err := parsePlatforms(archs)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
-	// check all of their atomic alignment
-	for _, pkg := range pkgs {
-		for _, arg := range gatherAtomicArguments(pkg) {
-			checkArgument(pkg, arg)
+	var misaligned [][]misalignment
+	atomicFields := make(map[*types.Var]bool)
+	for _, arg := range gatherAtomicArguments(pass) {
+		var broken []misalignment
+		for _, platform := range platforms {
+			m, ok := checkArgument(pass, platform, arg)
+			if !ok {
+				break
+			}
+			if m.field != nil {
+				atomicFields[m.field] = true
+			}
+			if !m.aligned() {
+				broken = append(broken, m)
+			}
+		}
+		if len(broken) > 0 {
+			misaligned = append(misaligned, broken)
 		}
 	}
 
-	// exit with the correct code
-	os.Exit(exit)
+	report(pass, misaligned, atomicFields)
+	checkMixedAccess(pass)
+	return nil, nil
+}
+
+// importsAtomic returns whether the package imports sync/atomic.
+func importsAtomic(pkg *types.Package) bool {
+	for _, imp := range pkg.Imports() {
+		if imp.Path() == "sync/atomic" {
+			return true
+		}
+	}
+	return false
 }
 
 // gatherAtomicArguments looks for calls to 64bit atomics and gathers their first
 // argument as an ast expression.
-func gatherAtomicArguments(pkg *packages.Package) (args []ast.Expr) {
-	for _, file := range pkg.Syntax {
+func gatherAtomicArguments(pass *analysis.Pass) (args []ast.Expr) {
+	for _, file := range pass.Files {
 		ast.Inspect(file, func(node ast.Node) bool {
 			call, ok := node.(*ast.CallExpr)
 			if !ok {
@@ -76,7 +95,7 @@ func gatherAtomicArguments(pkg *packages.Package) (args []ast.Expr) {
 			if !ok {
 				return true
 			}
-			name, ok := pkg.TypesInfo.Uses[ident].(*types.PkgName)
+			name, ok := pass.TypesInfo.Uses[ident].(*types.PkgName)
 			if !ok || name.Imported().Path() != "sync/atomic" {
 				return true
 			}
@@ -92,91 +111,160 @@ func gatherAtomicArguments(pkg *packages.Package) (args []ast.Expr) {
 	return args
 }
 
-// checkArgument makes sure that the ast expression is not an address of some field
-// access into a struct that is not 64 bit aligned.
-func checkArgument(pkg *packages.Package, arg ast.Expr) {
+// platform holds the sizes of a target architecture.
+type platform struct {
+	arch  string
+	sizes types.Sizes
+}
+
+// parsePlatforms returns the platforms for a comma separated list of
+// architectures.
+func parsePlatforms(archs string) (platforms []platform, err error) {
+	for _, arch := range strings.Split(archs, ",") {
+		arch = strings.TrimSpace(arch)
+		if arch == "" {
+			continue
+		}
+		sizes := types.SizesFor("gc", arch)
+		if sizes == nil {
+			return nil, fmt.Errorf("unknown architecture %q", arch)
+		}
+		platforms = append(platforms, platform{arch: arch, sizes: sizes})
+	}
+	if len(platforms) == 0 {
+		return nil, fmt.Errorf("no architectures to check")
+	}
+	return platforms, nil
+}
+
+// misalignment describes where a value whose address is passed to a 64-bit
+// atomic function is stored on a platform.
+type misalignment struct {
+	pos      token.Pos
+	platform platform
+	typ      types.Type // the atomically accessed type
+
+	offset int64 // offset from a 64-bit aligned address
+	stride int64 // the offset varies by multiples of stride with non-constant indices
+
+	field        *types.Var    // the atomically accessed field, if any
+	parent       *types.Struct // the struct directly containing field
+	named        *types.Named  // the named type of parent, if any
+	parentOffset int64         // offset of parent from a 64-bit aligned address
+}
+
+// aligned returns true if the value is always 64-bit aligned.
+func (m misalignment) aligned() bool {
+	return m.offset&7 == 0 && m.stride&7 == 0
+}
+
+// checkArgument locates the value whose address is passed as the ast
+// expression. It returns false if the expression is not an address of
+// expression or the location cannot be determined.
+func checkArgument(pass *analysis.Pass, platform platform, arg ast.Expr) (m misalignment, ok bool) {
 	// ensure the expression is an address of expression
 	unary, ok := arg.(*ast.UnaryExpr)
 	if !ok || unary.Op != token.AND {
-		return
+		return m, false
 	}
 
-	// gather the fields through the whole selection (&foo.bar.baz)
-	var fields []*types.Var
-	var root types.Type
-	var x = unary.X
-	for {
-		sel, ok := x.(*ast.SelectorExpr)
-		if !ok {
-			break
+	m, ok = locate(pass, platform.sizes, unary.X)
+	m.pos = arg.Pos()
+	m.platform = platform
+	m.typ = pass.TypesInfo.TypeOf(unary.X)
+	return m, ok
+}
+
+// locate computes the offset of the addressable expression from the start of
+// its root allocation, which is always 64-bit aligned. Embedded structs and
+// arrays are followed through to the variable or pointer they are stored in.
+func locate(pass *analysis.Pass, sizes types.Sizes, expr ast.Expr) (m misalignment, ok bool) {
+	switch expr := ast.Unparen(expr).(type) {
+	case *ast.SelectorExpr:
+		selection := pass.TypesInfo.Selections[expr]
+		if selection == nil {
+			// a package level variable
+			return m, true
 		}
-		field, ok := pkg.TypesInfo.Selections[sel].Obj().(*types.Var)
-		if !ok || !field.IsField() {
-			return
+		if selection.Kind() != types.FieldVal {
+			return m, false
 		}
-		fields = append(fields, field)
-		root = pkg.TypesInfo.Types[sel.X].Type
-		x = sel.X
-	}
-	if len(fields) == 0 {
-		return
-	}
-
-	// walk in reverse keeping track of the indicies walked through
-	// this helps deal with embedded fields, etc.
-	var indicies []int
-	for base := root; len(fields) > 0; fields = fields[:len(fields)-1] {
-		obj, index, _ := types.LookupFieldOrMethod(base, true, pkg.Types, fields[len(fields)-1].Name())
 
-		field, ok := obj.(*types.Var)
+		m, ok = locate(pass, sizes, expr.X)
 		if !ok {
-			return
+			return m, false
 		}
-		base = field.Type()
 
-		indicies = append(indicies, index...)
-	}
+		// walk through the embedded fields keeping track of offsets and
+		// indirections. pointers reset the offset since they point to the
+		// start of an allocation.
+		typ := selection.Recv()
+		for _, index := range selection.Index() {
+			base, wasPtr, ok := deref(typ)
+			if !ok {
+				return m, false
+			}
+			if wasPtr {
+				m.offset, m.stride = 0, 0
+			}
 
-	// derefrence the root to start off at the base struct
-	base, _, ok := deref(root)
-	if !ok {
-		return
-	}
+			m.parent, m.named, m.parentOffset = base, namedStruct(typ), m.offset
+			m.field = base.Field(index)
+			m.offset += sizes.Offsetsof(structFields(base))[index]
+			typ = m.field.Type()
+		}
+		return m, true
 
-	// now walk forward keeping track of offsets and indirections
-	var offset int64
-	var sizes = types.SizesFor("gc", "arm")
-	for _, index := range indicies {
-		// get the next field type and keep track of if it was a pointer. if so
-		// then we need to reset our offset (it's guaranteed 64 bit aligned).
-		next, wasPtr, ok := deref(base.Field(index).Type())
-		if wasPtr {
-			offset = 0
-		} else {
-			offset += sizes.Offsetsof(structFields(base))[index]
+	case *ast.IndexExpr:
+		var elem types.Type
+		switch t := pass.TypesInfo.TypeOf(expr.X).Underlying().(type) {
+		case *types.Array:
+			m, ok = locate(pass, sizes, expr.X)
+			if !ok {
+				return m, false
+			}
+			elem = t.Elem()
+		case *types.Pointer:
+			array, ok := t.Elem().Underlying().(*types.Array)
+			if !ok {
+				return m, false
+			}
+			elem = array.Elem()
+		case *types.Slice:
+			elem = t.Elem()
+		default:
+			return m, false
 		}
 
-		// if we're no longer at a struct, we're done walking.
-		if !ok {
-			break
+		// the element is no longer directly a field of a struct
+		m.field, m.parent, m.named = nil, nil, nil
+
+		size := sizes.Sizeof(elem)
+		if tv := pass.TypesInfo.Types[expr.Index]; tv.Value != nil {
+			if index, exact := constant.Int64Val(tv.Value); exact {
+				m.offset += index * size
+				return m, true
+			}
 		}
+		m.stride = gcd(m.stride, size)
+		return m, true
 
-		base = next
-	}
+	case *ast.StarExpr:
+		return m, true
 
-	// check if the offset is aligned
-	if offset&7 == 0 {
-		return
+	case *ast.Ident:
+		return m, true
 	}
 
-	// report an error and update the status code
-	file := pkg.Fset.File(arg.Pos())
-	line := file.Line(arg.Pos())
+	return m, false
+}
 
-	fmt.Fprintf(os.Stderr,
-		"%s:%d: address of non 64-bit aligned field passed to atomic (offset: %d)\n",
-		file.Name(), line, offset)
-	exit = 1
+// gcd returns the greatest common divisor of a and b.
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
 }
 
 // deref takes a type that can be
@@ -198,6 +286,15 @@ func deref(in types.Type) (*types.Struct, bool, bool) {
 	return out, wasPtr, ok
 }
 
+// namedStruct returns the named type of a possibly pointer to struct type.
+func namedStruct(in types.Type) *types.Named {
+	if ptr, ok := in.(*types.Pointer); ok {
+		in = ptr.Elem()
+	}
+	named, _ := in.(*types.Named)
+	return named
+}
+
 // structFields gathers all of the fields of the passed in struct.
 func structFields(in *types.Struct) []*types.Var {
 	out := make([]*types.Var, in.NumFields())
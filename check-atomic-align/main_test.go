@@ -0,0 +1,39 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func Test(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "a")
+}
+
+func TestArchs(t *testing.T) {
+	setArchs(t, "386,amd64")
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "archs")
+}
+
+func TestMixedAccess(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "mixed")
+}
+
+func TestSuggestedFixes(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, Analyzer, "fix")
+}
+
+func setArchs(t *testing.T, value string) {
+	previous := archs
+	if err := Analyzer.Flags.Set("archs", value); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { archs = previous })
+}
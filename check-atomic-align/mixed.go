@@ -0,0 +1,119 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// checkMixedAccess reports plain reads and writes of fields that are accessed
+// with sync/atomic functions elsewhere in the package. Mixing them is a data
+// race the race detector only notices when both paths run concurrently.
+func checkMixedAccess(pass *analysis.Pass) {
+	atomicFields := gatherAtomicFields(pass)
+	if len(atomicFields) == 0 {
+		return
+	}
+
+	// taking the address of a field does not access it, and the address may
+	// be passed on to a helper using sync/atomic.
+	addressed := make(map[*ast.SelectorExpr]bool)
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			if unary, ok := node.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+				if sel, ok := ast.Unparen(unary.X).(*ast.SelectorExpr); ok {
+					addressed[sel] = true
+				}
+			}
+			return true
+		})
+	}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			sel, ok := node.(*ast.SelectorExpr)
+			if !ok || addressed[sel] {
+				return true
+			}
+			field := selectedField(pass, sel)
+			if field == nil {
+				return true
+			}
+			atomicPos, ok := atomicFields[field]
+			if !ok {
+				return true
+			}
+
+			atomicPosition := pass.Fset.Position(atomicPos)
+			pass.Report(analysis.Diagnostic{
+				Pos:      sel.Sel.Pos(),
+				Category: "mixed",
+				Message: fmt.Sprintf("plain access to field %s, which is accessed atomically at %s:%d",
+					field.Name(), atomicPosition.Filename, atomicPosition.Line),
+			})
+			return true
+		})
+	}
+}
+
+// gatherAtomicFields returns the fields whose addresses are passed to
+// sync/atomic functions along with the position of the first such call.
+func gatherAtomicFields(pass *analysis.Pass) map[*types.Var]token.Pos {
+	fields := make(map[*types.Var]token.Pos)
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+			if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "sync/atomic" {
+				return true
+			}
+			if fn.Type().(*types.Signature).Recv() != nil {
+				// methods of the typed atomics
+				return true
+			}
+
+			unary, ok := call.Args[0].(*ast.UnaryExpr)
+			if !ok || unary.Op != token.AND {
+				return true
+			}
+			arg, ok := ast.Unparen(unary.X).(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if field := selectedField(pass, arg); field != nil {
+				if _, seen := fields[field]; !seen {
+					fields[field] = call.Pos()
+				}
+			}
+			return true
+		})
+	}
+	return fields
+}
+
+// selectedField returns the field selected by the expression, if any. Fields
+// of instantiated generic structs are mapped back to their origin.
+func selectedField(pass *analysis.Pass, sel *ast.SelectorExpr) *types.Var {
+	selection := pass.TypesInfo.Selections[sel]
+	if selection == nil || selection.Kind() != types.FieldVal {
+		return nil
+	}
+	field, ok := selection.Obj().(*types.Var)
+	if !ok {
+		return nil
+	}
+	return field.Origin()
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package a
+
+import "sync/atomic"
+
+type misaligned struct {
+	flag  int32
+	count int64
+}
+
+func badField(m *misaligned) {
+	atomic.AddInt64(&m.count, 1) // want `address of non 64-bit aligned field passed to atomic on 386, arm, mips \(offset: 4\)\n\tconsider declaring count as atomic.Int64, which is always 64-bit aligned\n\tor reorder the fields of misaligned as: count, flag`
+}
+
+type padded struct {
+	flag  int32
+	_     int32
+	count int64
+}
+
+func goodPadded(p *padded) {
+	atomic.AddInt64(&p.count, 1)
+}
+
+type first struct {
+	count uint64
+	flag  int32
+}
+
+func goodFirst(f *first) {
+	atomic.AddUint64(&f.count, 1)
+}
+
+type typed struct {
+	flag  int32
+	count atomic.Int64
+}
+
+func goodTyped(t *typed) {
+	t.count.Add(1)
+}
+
+type outer struct {
+	flag  int32
+	inner first
+}
+
+func badEmbedded(o *outer) {
+	atomic.LoadUint64(&o.inner.count) // want `on 386, arm, mips \(offset: 4\)\n\tconsider declaring count as atomic.Uint64`
+}
+
+func goodIndex(values *[4]int64, i int) {
+	atomic.AddInt64(&values[1], 1)
+	atomic.AddInt64(&values[i], 1)
+}
+
+type slot struct {
+	count int64
+	flag  int32
+}
+
+func badIndex(slots *[4]slot) {
+	atomic.AddInt64(&slots[1].count, 1) // want `on 386, arm, mips \(offset: 12\)`
+}
+
+func badSliceStride(slots []slot, i int) {
+	atomic.AddInt64(&slots[i].count, 1) // want `on 386, arm, mips \(offset: 0 \+ 12\*i\)`
+}
+
+type pair struct {
+	flag   int32
+	values [2]int64
+}
+
+func badArrayField(p *pair) {
+	atomic.AddInt64(&p.values[0], 1) // want `on 386, arm, mips \(offset: 4\)\n\tconsider storing it as atomic.Int64`
+}
@@ -0,0 +1,15 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package archs
+
+import "sync/atomic"
+
+type counter struct {
+	flag  int32
+	count int64
+}
+
+func add(c *counter) {
+	atomic.AddInt64(&c.count, 1) // want `on 386 \(offset: 4\)\n`
+}
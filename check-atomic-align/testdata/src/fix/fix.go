@@ -0,0 +1,46 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package fix
+
+import "sync/atomic"
+
+type counter struct {
+	// closed is set once.
+	closed int32
+	total  int64 // total number of calls
+	name   string
+}
+
+func add(c *counter) {
+	atomic.AddInt64(&c.total, 1) // want `or reorder the fields of counter as: total, closed, name$`
+}
+
+type Exported struct {
+	Closed int32
+	Total  int64
+}
+
+func addExported(e *Exported) {
+	atomic.AddInt64(&e.Total, 1) // want `\(not fixed automatically: Exported is exported\)`
+}
+
+type unkeyed struct {
+	closed int32
+	total  int64
+}
+
+var zero = unkeyed{0, 0}
+
+func addUnkeyed(u *unkeyed) {
+	atomic.AddInt64(&u.total, 1) // want `\(not fixed automatically: unkeyed composite literal at .*fix.go:33:12\)`
+}
+
+type tested struct {
+	closed int32
+	total  int64
+}
+
+func addTested(t *tested) {
+	atomic.AddInt64(&t.total, 1) // want `\(not fixed automatically: unkeyed composite literal at .*fix_test.go:6:22\)`
+}
@@ -0,0 +1,6 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package fix
+
+var cases = []tested{{1, 2}}
@@ -0,0 +1,48 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package mixed
+
+import "sync/atomic"
+
+type stats struct {
+	count int64
+	other int64
+	typed atomic.Int64
+}
+
+func increment(s *stats) {
+	atomic.AddInt64(&s.count, 1)
+	s.typed.Add(1)
+}
+
+func read(s *stats) int64 {
+	s.other++
+	return s.count // want `plain access to field count, which is accessed atomically at .*mixed.go:15`
+}
+
+func reset(s *stats) {
+	s.count = 0 // want `plain access to field count`
+	s.typed.Store(0)
+}
+
+func load(s *stats) int64 {
+	return atomic.LoadInt64(&s.count)
+}
+
+func address(s *stats) *int64 {
+	return &s.count
+}
+
+type generic[T any] struct {
+	count int64
+	value T
+}
+
+func incrementGeneric(g *generic[string]) {
+	atomic.AddInt64(&g.count, 1)
+}
+
+func readGeneric(g *generic[int]) int64 {
+	return g.count // want `plain access to field count`
+}
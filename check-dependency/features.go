@@ -0,0 +1,136 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Platform is a GOOS/GOARCH and build tag combination to load packages with.
+type Platform struct {
+	Name   string
+	GOOS   string
+	GOARCH string
+	Tags   []string
+}
+
+// ParsePlatform parses a "GOOS/GOARCH[:tag,tag]" specification.
+func ParsePlatform(spec string) (Platform, error) {
+	platform := Platform{Name: spec}
+
+	target, tags, _ := strings.Cut(spec, ":")
+	goos, goarch, ok := strings.Cut(target, "/")
+	if !ok || goos == "" || goarch == "" {
+		return platform, fmt.Errorf("invalid platform %q, expected GOOS/GOARCH[:tag,tag]", spec)
+	}
+	platform.GOOS, platform.GOARCH = goos, goarch
+
+	for _, tag := range strings.Split(tags, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			platform.Tags = append(platform.Tags, tag)
+		}
+	}
+	return platform, nil
+}
+
+// Config returns the configuration for loading packages on the platform.
+//
+// cgo is disabled by default when cross-compiling or when no C compiler is
+// found, which would leave the files importing "C" out of the packages, so
+// it's always enabled, for the host as well.
+func (platform Platform) Config(includeTests bool) *packages.Config {
+	config := &packages.Config{
+		Mode:  packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedFiles,
+		Tests: includeTests,
+	}
+	config.Env = append(os.Environ(), "CGO_ENABLED=1")
+	if platform.GOOS != "" {
+		config.Env = append(config.Env, "GOOS="+platform.GOOS, "GOARCH="+platform.GOARCH)
+	}
+	if len(platform.Tags) > 0 {
+		config.BuildFlags = []string{"-tags=" + strings.Join(platform.Tags, ",")}
+	}
+	return config
+}
+
+// forbiddenFeatures finds dependencies outside of the standard library
+// using cgo or unsafe.
+type forbiddenFeatures struct {
+	cgo    bool
+	unsafe bool
+	except []*regexp.Regexp
+
+	usesCgo map[*packages.Package]bool
+}
+
+func newForbiddenFeatures(cgo, unsafe bool, except []*regexp.Regexp) *forbiddenFeatures {
+	return &forbiddenFeatures{
+		cgo:     cgo,
+		unsafe:  unsafe,
+		except:  except,
+		usesCgo: map[*packages.Package]bool{},
+	}
+}
+
+// find returns the packages reachable from root that use a forbidden feature.
+func (features *forbiddenFeatures) find(root *packages.Package) []Match {
+	return findAllFunc(root, func(p *packages.Package) []Match {
+		if isStd(p.PkgPath) || matchesOne(p.PkgPath, features.except) != "" {
+			return nil
+		}
+
+		// cgo packages usually import unsafe as well, so the features are
+		// reported together as a single match
+		var uses []string
+		if features.cgo && features.cgoPackage(p) {
+			uses = append(uses, "cgo")
+		}
+		if _, ok := p.Imports["unsafe"]; features.unsafe && ok {
+			uses = append(uses, "unsafe")
+		}
+		if len(uses) == 0 {
+			return nil
+		}
+		return []Match{{Feature: strings.Join(uses, ", ")}}
+	})
+}
+
+// cgoPackage returns whether any of the package files imports "C".
+func (features *forbiddenFeatures) cgoPackage(pkg *packages.Package) bool {
+	if uses, ok := features.usesCgo[pkg]; ok {
+		return uses
+	}
+
+	uses := false
+	fset := token.NewFileSet()
+	for _, filename := range pkg.GoFiles {
+		file, err := parser.ParseFile(fset, filename, nil, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, spec := range file.Imports {
+			if path, err := strconv.Unquote(spec.Path.Value); err == nil && path == "C" {
+				uses = true
+			}
+		}
+	}
+
+	features.usesCgo[pkg] = uses
+	return uses
+}
+
+// isStd returns whether the package path belongs to the standard library,
+// which is the case when its first element has no dot.
+func isStd(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestParsePlatform(t *testing.T) {
+	platform, err := ParsePlatform("linux/arm:purego, noasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Platform{Name: "linux/arm:purego, noasm", GOOS: "linux", GOARCH: "arm", Tags: []string{"purego", "noasm"}}
+	if !reflect.DeepEqual(platform, want) {
+		t.Errorf("got %+v, want %+v", platform, want)
+	}
+
+	for _, spec := range []string{"linux", "/amd64", "linux/:purego"} {
+		if _, err := ParsePlatform(spec); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}
+
+func TestForbidUnsafe(t *testing.T) {
+	unsafe := &packages.Package{PkgPath: "unsafe"}
+	sync := &packages.Package{PkgPath: "sync/atomic", Imports: map[string]*packages.Package{"unsafe": unsafe}}
+	fast := &packages.Package{PkgPath: "github.com/fast/hash", Imports: map[string]*packages.Package{"unsafe": unsafe, "sync/atomic": sync}}
+	ok := &packages.Package{PkgPath: "github.com/fast/ok", Imports: map[string]*packages.Package{"unsafe": unsafe}}
+	root := &packages.Package{PkgPath: "storj.io/uplink", Imports: map[string]*packages.Package{
+		"github.com/fast/hash": fast,
+		"github.com/fast/ok":   ok,
+		"sync/atomic":          sync,
+	}}
+
+	features := newForbiddenFeatures(false, true, stringsToRegexps([]string{"fast/ok$"}))
+	want := []Match{{
+		Root:    "storj.io/uplink",
+		Package: "github.com/fast/hash",
+		Feature: "unsafe",
+		Path:    []string{"storj.io/uplink", "github.com/fast/hash"},
+	}}
+	if got := features.find(root); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if got := want[0].String(); got != "storj.io/uplink => github.com/fast/hash (uses unsafe)" {
+		t.Errorf("unexpected string %q", got)
+	}
+}
+
+func TestForbidCgo(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":           "module example.com/app\n\ngo 1.24\n",
+		"main.go":          "package main\n\nimport _ \"example.com/app/native\"\n\nfunc main() {}\n",
+		"native/native.go": "package native\n\n// int answer() { return 42; }\nimport \"C\"\nimport \"unsafe\"\n\nfunc Answer() int { return int(C.answer()) + int(unsafe.Sizeof(0)) }\n",
+		"native/purego.go": "//go:build !cgo\n\npackage native\n\nfunc Answer() int { return 42 }\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// cgo is disabled on the host as if there was no C compiler, and on a
+	// platform other than the host by default
+	t.Setenv("CGO_ENABLED", "0")
+	cross := Platform{Name: "linux/arm", GOOS: "linux", GOARCH: "arm"}
+	if runtime.GOOS == cross.GOOS && runtime.GOARCH == cross.GOARCH {
+		cross = Platform{Name: "linux/386", GOOS: "linux", GOARCH: "386"}
+	}
+
+	for _, platform := range []Platform{{}, cross} {
+		config := platform.Config(false)
+		config.Dir = dir
+		roots, err := packages.Load(config, ".")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(roots) != 1 || len(roots[0].Errors) > 0 {
+			t.Fatalf("unexpected packages %v", roots)
+		}
+
+		features := newForbiddenFeatures(true, true, nil)
+		want := []Match{{
+			Root:    "example.com/app",
+			Package: "example.com/app/native",
+			Feature: "cgo, unsafe",
+			Path:    []string{"example.com/app", "example.com/app/native"},
+		}}
+		if got := features.find(roots[0]); !reflect.DeepEqual(got, want) {
+			t.Errorf("%q: got %+v, want %+v", platform.Name, got, want)
+		}
+	}
+}
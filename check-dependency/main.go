@@ -4,6 +4,7 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
@@ -36,13 +37,32 @@ func (ss *Strings) Set(v string) error {
 var verbose bool
 
 func main() {
+	// the size report has flags of its own, so -size must come first
+	if len(os.Args) > 1 && (os.Args[1] == "-size" || os.Args[1] == "--size") {
+		os.Exit(runSize(os.Args[2:]))
+	}
+
 	var ignore Strings
 	var check Strings
 	var except Strings
 	var includeTests bool
+	var all bool
+	var jsonOutput bool
+	var modulesPath string
+	var forbidCgo bool
+	var forbidUnsafe bool
+	var platforms Strings
+	var size bool
 
 	flag.BoolVar(&verbose, "verbose", false, "print debug information")
 	flag.BoolVar(&includeTests, "include-tests", false, "also check test packages")
+	flag.BoolVar(&all, "all", false, "report every matching package with its shortest path instead of the first match")
+	flag.BoolVar(&jsonOutput, "json", false, "print all matches as JSON to stdout")
+	flag.StringVar(&modulesPath, "modules", "", "JSON file with module rules to check the build list against")
+	flag.BoolVar(&forbidCgo, "forbid-cgo", false, "fail when a non-std dependency has cgo files")
+	flag.BoolVar(&forbidUnsafe, "forbid-unsafe", false, "fail when a non-std dependency imports unsafe")
+	flag.Var(&platforms, "platforms", "check for these GOOS/GOARCH[:tag,tag] combinations")
+	flag.BoolVar(&size, "size", false, "report the binary size of every module instead, must be the first argument followed by the size flags")
 
 	flag.Var(&ignore, "ignore", "ignore packages matching regular expression when listing")
 	flag.Var(&check, "check", "succeeds when contains a package matching regular expression")
@@ -50,48 +70,117 @@ func main() {
 
 	flag.Parse()
 
+	if size {
+		fmt.Fprintln(os.Stderr, "-size must be the first argument")
+		os.Exit(2)
+	}
+
 	pkgNames := flag.Args()
 	if len(pkgNames) == 0 {
 		pkgNames = []string{"."}
 	}
 
-	roots, err := packages.Load(&packages.Config{
-		Mode:  packages.NeedName | packages.NeedImports | packages.NeedDeps,
-		Tests: includeTests,
-	}, pkgNames...)
-	if err != nil {
-		panic(err)
-	}
-
 	rxIgnore := stringsToRegexps(ignore)
 	rxCheck := stringsToRegexps(check)
 	rxExcept := stringsToRegexps(except)
 
-	if verbose {
-		fmt.Fprintln(os.Stderr, "loaded roots:", packagesToStrings(roots))
-		fmt.Fprintln(os.Stderr, "ignore:", ignore)
-		fmt.Fprintln(os.Stderr, "check:", check)
-		fmt.Fprintln(os.Stderr, "except:", except)
+	targets := []Platform{{}}
+	if len(platforms) > 0 {
+		targets = nil
+		for _, spec := range platforms {
+			platform, err := ParsePlatform(spec)
+			if err != nil {
+				panic(err)
+			}
+			targets = append(targets, platform)
+		}
 	}
 
 	var exitCode int
-	for _, root := range roots {
+	var matches []Match
+	for _, platform := range targets {
+		roots, err := packages.Load(platform.Config(includeTests), pkgNames...)
+		if err != nil {
+			panic(err)
+		}
+
 		if verbose {
-			fmt.Fprintln(os.Stderr, "# ", root.PkgPath)
+			if platform.Name != "" {
+				fmt.Fprintln(os.Stderr, "platform:", platform.Name)
+			}
+			fmt.Fprintln(os.Stderr, "loaded roots:", packagesToStrings(roots))
+			fmt.Fprintln(os.Stderr, "ignore:", ignore)
+			fmt.Fprintln(os.Stderr, "check:", check)
+			fmt.Fprintln(os.Stderr, "except:", except)
 		}
-		if target := matchesOne(root.PkgPath, rxIgnore); target != "" {
+
+		forbidden := newForbiddenFeatures(forbidCgo, forbidUnsafe, rxExcept)
+		for _, root := range roots {
 			if verbose {
-				fmt.Fprintf(os.Stderr, "    skipping because it matched filter %q\n", target)
+				fmt.Fprintln(os.Stderr, "# ", root.PkgPath)
 			}
-			continue
+			if target := matchesOne(root.PkgPath, rxIgnore); target != "" {
+				if verbose {
+					fmt.Fprintf(os.Stderr, "    skipping because it matched filter %q\n", target)
+				}
+				continue
+			}
+
+			var found []Match
+			if forbidCgo || forbidUnsafe {
+				found = append(found, forbidden.find(root)...)
+			}
+
+			if all || jsonOutput || platform.Name != "" {
+				found = append(found, findAll(root, rxCheck, rxExcept)...)
+			} else if target := findPath(root, rxCheck, rxExcept); target != "" {
+				fmt.Fprintln(os.Stderr, target)
+				exitCode = 1
+			}
+
+			for i := range found {
+				found[i].Platform = platform.Name
+			}
+			if len(found) > 0 {
+				exitCode = 1
+			}
+			matches = append(matches, found...)
 		}
+	}
 
-		if target := findPath(root, rxCheck, rxExcept); target != "" {
-			fmt.Fprintln(os.Stderr, target)
+	var moduleViolations []ModuleViolation
+	if modulesPath != "" {
+		rules, err := LoadModuleRules(modulesPath)
+		if err != nil {
+			panic(err)
+		}
+		modules, err := ListModules()
+		if err != nil {
+			panic(err)
+		}
+		moduleViolations = CheckModules(rules, modules)
+		if len(moduleViolations) > 0 {
 			exitCode = 1
 		}
 	}
 
+	if jsonOutput {
+		report := NewReport(matches)
+		report.Modules = moduleViolations
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		if err := enc.Encode(report); err != nil {
+			panic(err)
+		}
+	} else {
+		for _, match := range matches {
+			fmt.Fprintln(os.Stderr, match)
+		}
+		for _, violation := range moduleViolations {
+			fmt.Fprintln(os.Stderr, violation)
+		}
+	}
+
 	os.Exit(exitCode)
 }
 
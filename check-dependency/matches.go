@@ -0,0 +1,130 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Match is a dependency of a root package matching a -check expression or
+// using a forbidden feature.
+type Match struct {
+	Root    string `json:"root"`
+	Package string `json:"package"`
+	Pattern string `json:"pattern,omitempty"`
+	// Feature lists the forbidden features the package uses, "cgo",
+	// "unsafe" or "cgo, unsafe".
+	Feature string `json:"feature,omitempty"`
+	// Platform is the -platforms entry the match was found with.
+	Platform string `json:"platform,omitempty"`
+	// Path is the shortest import path from Root to Package.
+	Path []string `json:"path"`
+}
+
+// String formats the match the same way as findPath.
+func (match Match) String() string {
+	s := strings.Join(match.Path, " => ")
+	if match.Pattern != "" {
+		s += " /" + match.Pattern + "/"
+	}
+	if match.Feature != "" {
+		s += " (uses " + match.Feature + ")"
+	}
+	if match.Platform != "" {
+		s = "[" + match.Platform + "] " + s
+	}
+	return s
+}
+
+// Report is the JSON output of all matches.
+type Report struct {
+	Matches []Match `json:"matches"`
+	// Packages counts the root packages pulling in each matched package.
+	Packages map[string]int `json:"packages"`
+	// Modules lists the violations of the -modules rules.
+	Modules []ModuleViolation `json:"modules,omitempty"`
+}
+
+// NewReport creates a report of the matches.
+func NewReport(matches []Match) Report {
+	report := Report{Matches: matches, Packages: map[string]int{}}
+	if report.Matches == nil {
+		report.Matches = []Match{}
+	}
+	for _, match := range matches {
+		report.Packages[match.Package]++
+	}
+	return report
+}
+
+// findAll returns every package in the dependencies of pkg that matches, along
+// with the shortest import path to it.
+func findAll(pkg *packages.Package, match, except []*regexp.Regexp) []Match {
+	return findAllFunc(pkg, func(p *packages.Package) []Match {
+		if matched := matchesOne(p.PkgPath, match); matched != "" {
+			if exception := matchesOne(p.PkgPath, except); exception == "" {
+				return []Match{{Pattern: matched}}
+			}
+		}
+		return nil
+	})
+}
+
+// findAllFunc calls check for every package in the dependencies of pkg and
+// completes the returned matches with the shortest import path to it.
+func findAllFunc(pkg *packages.Package, check func(*packages.Package) []Match) []Match {
+	importedBy := map[*packages.Package]*packages.Package{pkg: nil}
+	queue := []*packages.Package{pkg}
+
+	var matches []Match
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+
+		if found := check(p); len(found) > 0 {
+			var path []string
+			for at := p; at != nil; at = importedBy[at] {
+				path = append(path, at.PkgPath)
+			}
+			slices.Reverse(path)
+
+			for _, match := range found {
+				match.Root = pkg.PkgPath
+				match.Package = p.PkgPath
+				match.Path = path
+				matches = append(matches, match)
+			}
+		}
+
+		for _, c := range sortedImports(p) {
+			if _, ok := importedBy[c]; ok {
+				continue
+			}
+			importedBy[c] = p
+			queue = append(queue, c)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, k int) bool {
+		return matches[i].Package < matches[k].Package
+	})
+	return matches
+}
+
+// sortedImports returns the imports of pkg sorted by their path.
+func sortedImports(pkg *packages.Package) []*packages.Package {
+	imports := make([]*packages.Package, 0, len(pkg.Imports))
+	for _, imp := range pkg.Imports {
+		imports = append(imports, imp)
+	}
+	sort.Slice(imports, func(i, k int) bool {
+		return imports[i].PkgPath < imports[k].PkgPath
+	})
+	return imports
+}
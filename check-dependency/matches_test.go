@@ -0,0 +1,46 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestFindAll(t *testing.T) {
+	pkg := func(path string, imports ...*packages.Package) *packages.Package {
+		p := &packages.Package{PkgPath: path, Imports: map[string]*packages.Package{}}
+		for _, imp := range imports {
+			p.Imports[imp.PkgPath] = imp
+		}
+		return p
+	}
+
+	pgx := pkg("github.com/jackc/pgx/v5")
+	pgconn := pkg("github.com/jackc/pgx/v5/pgconn")
+	db := pkg("storj.io/uplink/db", pgx, pgconn)
+	util := pkg("storj.io/uplink/util", db)
+	root := pkg("storj.io/uplink", util, pkg("storj.io/uplink/other", pgconn))
+
+	matches := findAll(root, stringsToRegexps([]string{"jackc/pgx"}), stringsToRegexps([]string{"pgconn$"}))
+	want := []Match{{
+		Root:    "storj.io/uplink",
+		Package: "github.com/jackc/pgx/v5",
+		Pattern: "jackc/pgx",
+		Path:    []string{"storj.io/uplink", "storj.io/uplink/util", "storj.io/uplink/db", "github.com/jackc/pgx/v5"},
+	}}
+	if !reflect.DeepEqual(matches, want) {
+		t.Errorf("got %+v, want %+v", matches, want)
+	}
+
+	matches = findAll(root, stringsToRegexps([]string{"jackc/pgx"}), nil)
+	if len(matches) != 2 || matches[1].Package != "github.com/jackc/pgx/v5/pgconn" || len(matches[1].Path) != 3 {
+		t.Errorf("unexpected matches %+v", matches)
+	}
+	if report := NewReport(append(matches, matches...)); report.Packages["github.com/jackc/pgx/v5"] != 2 {
+		t.Errorf("unexpected report %+v", report)
+	}
+}
@@ -0,0 +1,279 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/blang/semver/v4"
+)
+
+// ModuleRules declares the module level checks, loaded with -modules from a
+// JSON file, e.g.:
+//
+//	{
+//		"forbidden": [
+//			{"path": "^github\\.com/jackc/pgx$", "message": "use pgx/v5"},
+//			{"path": "^golang\\.org/x/net$", "versions": "<0.23.0", "message": "CVE-2023-45288"}
+//		],
+//		"licenses": ["Apache-2.0", "MIT", "BSD-2-Clause", "BSD-3-Clause", "ISC", "MPL-2.0"],
+//		"license_exceptions": ["^storj\\.io/"],
+//		"allow_replace": ["^github\\.com/spacemonkeygo/monkit/v3$"]
+//	}
+type ModuleRules struct {
+	Forbidden []ForbiddenModule `json:"forbidden,omitempty"`
+	// Licenses is the allow-list of license identifiers. Licenses are not
+	// checked when it's empty.
+	Licenses []string `json:"licenses,omitempty"`
+	// LicenseExceptions are regular expressions of modules exempt from the
+	// license check.
+	LicenseExceptions []string `json:"license_exceptions,omitempty"`
+	// AllowReplace are regular expressions of modules that may be replaced
+	// by replace directives.
+	AllowReplace []string `json:"allow_replace,omitempty"`
+}
+
+// ForbiddenModule forbids modules matching the regular expression Path, and
+// when Versions is set, only the versions in that semver range.
+type ForbiddenModule struct {
+	Path     string `json:"path"`
+	Versions string `json:"versions,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// Module is a module in the build list as printed by "go list -m -json".
+type Module struct {
+	Path    string
+	Version string
+	Main    bool
+	Dir     string
+	Replace *Module
+}
+
+// ModuleViolation is a module breaking one of the module rules.
+type ModuleViolation struct {
+	Module  string `json:"module"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// String formats the violation for printing.
+func (violation ModuleViolation) String() string {
+	return fmt.Sprintf("module %s %s: %s: %s", violation.Module, violation.Version, violation.Kind, violation.Message)
+}
+
+// LoadModuleRules loads a JSON module rules file.
+func LoadModuleRules(path string) (ModuleRules, error) {
+	var rules ModuleRules
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rules, err
+	}
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return rules, fmt.Errorf("invalid module rules %v: %w", path, err)
+	}
+	for i, forbidden := range rules.Forbidden {
+		if forbidden.Path == "" {
+			return rules, fmt.Errorf("invalid module rules %v: forbidden module %d has no path", path, i+1)
+		}
+		if _, err := regexp.Compile(forbidden.Path); err != nil {
+			return rules, fmt.Errorf("invalid module rules %v: %w", path, err)
+		}
+		if forbidden.Versions != "" {
+			if _, err := semver.ParseRange(forbidden.Versions); err != nil {
+				return rules, fmt.Errorf("invalid module rules %v: %w", path, err)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// ListModules returns the build list of the main module.
+func ListModules() ([]Module, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", "list", "-m", "-json", "all")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list -m failed: %w\n%s", err, stderr.String())
+	}
+
+	var modules []Module
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var module Module
+		if err := dec.Decode(&module); err != nil {
+			if errors.Is(err, io.EOF) {
+				return modules, nil
+			}
+			return nil, err
+		}
+		modules = append(modules, module)
+	}
+}
+
+// CheckModules verifies the modules against the rules.
+func CheckModules(rules ModuleRules, modules []Module) []ModuleViolation {
+	licenseExceptions := stringsToRegexps(rules.LicenseExceptions)
+	allowReplace := stringsToRegexps(rules.AllowReplace)
+	forbiddenPaths := make([]*regexp.Regexp, len(rules.Forbidden))
+	for i, forbidden := range rules.Forbidden {
+		forbiddenPaths[i] = regexp.MustCompile(forbidden.Path)
+	}
+
+	var violations []ModuleViolation
+	for _, module := range modules {
+		if module.Main {
+			continue
+		}
+		report := func(kind, message string) {
+			violations = append(violations, ModuleViolation{
+				Module:  module.Path,
+				Version: module.Version,
+				Kind:    kind,
+				Message: message,
+			})
+		}
+
+		for i, forbidden := range rules.Forbidden {
+			if !forbiddenPaths[i].MatchString(module.Path) {
+				continue
+			}
+			if forbidden.Versions != "" && !inRange(module.Version, forbidden.Versions) {
+				continue
+			}
+			message := "forbidden by /" + forbidden.Path + "/"
+			if forbidden.Versions != "" {
+				message += " " + forbidden.Versions
+			}
+			if forbidden.Message != "" {
+				message += ": " + forbidden.Message
+			}
+			report("forbidden", message)
+		}
+
+		dir := module.Dir
+		if module.Replace != nil {
+			if matchesOne(module.Path, allowReplace) == "" {
+				report("replace", "replaced by "+strings.TrimSpace(module.Replace.Path+" "+module.Replace.Version))
+			}
+			dir = module.Replace.Dir
+		}
+
+		if len(rules.Licenses) == 0 || matchesOne(module.Path, licenseExceptions) != "" {
+			continue
+		}
+		if dir == "" {
+			report("license", "module is not in the module cache, run go mod download")
+			continue
+		}
+		license := FindLicense(dir)
+		if license == "" {
+			report("license", "no known license found")
+			continue
+		}
+		allowed := false
+		for _, name := range rules.Licenses {
+			allowed = allowed || name == license
+		}
+		if !allowed {
+			report("license", license+" is not allowed")
+		}
+	}
+	return violations
+}
+
+// inRange returns whether the module version is in the semver range. Versions
+// that cannot be parsed are never in the range.
+func inRange(version, versions string) bool {
+	v, err := semver.ParseTolerant(version)
+	if err != nil {
+		return false
+	}
+	r, err := semver.ParseRange(versions)
+	if err != nil {
+		return false
+	}
+	return r(v)
+}
+
+// FindLicense classifies the license file in the root of the module directory.
+func FindLicense(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		name := strings.ToUpper(entry.Name())
+		if entry.IsDir() || !(strings.HasPrefix(name, "LICENSE") || strings.HasPrefix(name, "LICENCE") || strings.HasPrefix(name, "COPYING")) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		if license := ClassifyLicense(string(data)); license != "" {
+			return license
+		}
+	}
+	return ""
+}
+
+// licensePhrases identifies licenses by phrases in their text. The first
+// phrase is the title of the license, or the sentence it starts with when it
+// has none. The phrases are lower case with collapsed whitespace.
+var licensePhrases = []struct {
+	name    string
+	phrases []string
+}{
+	{"AGPL-3.0", []string{"gnu affero general public license version 3"}},
+	{"LGPL-3.0", []string{"gnu lesser general public license version 3"}},
+	{"LGPL-2.1", []string{"gnu lesser general public license version 2.1"}},
+	{"GPL-3.0", []string{"gnu general public license version 3"}},
+	{"GPL-2.0", []string{"gnu general public license version 2"}},
+	{"MPL-2.0", []string{"mozilla public license version 2.0"}},
+	{"Apache-2.0", []string{"apache license version 2.0"}},
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+	{"ISC", []string{"permission to use, copy, modify, and/or distribute this software for any purpose with or without fee"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "the names of its contributors may not be used"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+	{"Unlicense", []string{"this is free and unencumbered software released into the public domain"}},
+	{"CC0-1.0", []string{"creative commons legal code", "cc0 1.0 universal"}},
+}
+
+// ClassifyLicense returns the SPDX identifier of the license text, or an
+// empty string when it's not recognized. Licenses refer to each other, for
+// example MPL-2.0 names the GNU licenses it's compatible with, so the license
+// whose title comes first in the text wins.
+func ClassifyLicense(text string) string {
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+
+	name, first := "", len(text)
+	for _, license := range licensePhrases {
+		at := strings.Index(text, license.phrases[0])
+		if at < 0 || at >= first {
+			continue
+		}
+		matches := true
+		for _, phrase := range license.phrases[1:] {
+			matches = matches && strings.Contains(text, phrase)
+		}
+		if matches {
+			name, first = license.name, at
+		}
+	}
+	return name
+}
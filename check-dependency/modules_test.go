@@ -0,0 +1,83 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestClassifyLicense(t *testing.T) {
+	for text, want := range map[string]string{
+		"Apache License\n  Version 2.0, January 2004":                                       "Apache-2.0",
+		"Permission is hereby granted, free of charge, to any person obtaining a copy":      "MIT",
+		"Redistribution and use in source and binary forms ...\n * Neither the name of":     "BSD-3-Clause",
+		"Redistribution and use in source and binary\n forms, with or without modification": "BSD-2-Clause",
+		"GNU AFFERO GENERAL PUBLIC LICENSE\nVersion 3, 19 November 2007":                    "AGPL-3.0",
+		"Mozilla Public License Version 2.0":                                                "MPL-2.0",
+		"All rights reserved.":                                                              "",
+	} {
+		if got := ClassifyLicense(text); got != want {
+			t.Errorf("ClassifyLicense(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestClassifyLicenseText(t *testing.T) {
+	for _, want := range []string{"Apache-2.0", "GPL-3.0", "LGPL-2.1", "LGPL-3.0", "MPL-2.0"} {
+		data, err := os.ReadFile(filepath.Join("testdata", "licenses", want))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ClassifyLicense(string(data)); got != want {
+			t.Errorf("ClassifyLicense(%s) = %q", want, got)
+		}
+	}
+}
+
+func TestCheckModules(t *testing.T) {
+	mit := t.TempDir()
+	if err := os.WriteFile(filepath.Join(mit, "LICENSE"), []byte("Permission is hereby granted, free of charge"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gpl := t.TempDir()
+	if err := os.WriteFile(filepath.Join(gpl, "COPYING"), []byte("GNU GENERAL PUBLIC LICENSE Version 3"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := ModuleRules{
+		Forbidden: []ForbiddenModule{
+			{Path: `^example\.com/old$`, Versions: "<1.2.0", Message: "upgrade"},
+		},
+		Licenses:          []string{"MIT"},
+		LicenseExceptions: []string{`^storj\.io/`},
+		AllowReplace:      []string{`^example\.com/fork$`},
+	}
+	modules := []Module{
+		{Path: "storj.io/main", Main: true},
+		{Path: "example.com/old", Version: "v1.1.9", Dir: mit},
+		{Path: "example.com/old", Version: "v1.2.0", Dir: mit},
+		{Path: "example.com/gpl", Version: "v0.1.0", Dir: gpl},
+		{Path: "example.com/missing", Version: "v0.1.0"},
+		{Path: "example.com/fork", Version: "v1.0.0", Replace: &Module{Path: "example.com/myfork", Version: "v1.0.1", Dir: mit}},
+		{Path: "example.com/local", Version: "v1.0.0", Replace: &Module{Path: "../local", Dir: mit}},
+		{Path: "storj.io/common", Version: "v0.0.0-20260101000000-abcdefabcdef"},
+	}
+
+	var got []string
+	for _, violation := range CheckModules(rules, modules) {
+		got = append(got, violation.String())
+	}
+	want := []string{
+		`module example.com/old v1.1.9: forbidden: forbidden by /^example\.com/old$/ <1.2.0: upgrade`,
+		`module example.com/gpl v0.1.0: license: GPL-3.0 is not allowed`,
+		`module example.com/missing v0.1.0: license: module is not in the module cache, run go mod download`,
+		`module example.com/local v1.0.0: replace: replaced by ../local`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
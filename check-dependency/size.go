@@ -0,0 +1,305 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"debug/buildinfo"
+	"debug/elf"
+	"debug/gosym"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const unattributed = "<unattributed>"
+
+// SizeReport attributes the bytes of a binary to packages and modules.
+type SizeReport struct {
+	Total    uint64            `json:"total"`
+	Modules  map[string]uint64 `json:"modules"`
+	Packages map[string]uint64 `json:"packages,omitempty"`
+}
+
+// runSize implements the -size mode, which builds a main package and
+// reports how many bytes of the binary every module accounts for.
+func runSize(args []string) int {
+	var tags, binary, baselinePath string
+	var writeBaseline, showPackages, jsonOutput bool
+	var threshold uint64
+	var top int
+	var watch Strings
+
+	flags := flag.NewFlagSet("-size", flag.ExitOnError)
+	flags.StringVar(&tags, "tags", "", "build tags to build the binary with")
+	flags.StringVar(&binary, "binary", "", "analyze this ELF binary instead of building the package")
+	flags.StringVar(&baselinePath, "baseline", "", "JSON file with the module sizes to compare against")
+	flags.BoolVar(&writeBaseline, "write-baseline", false, "write the current module sizes to the -baseline file")
+	flags.Uint64Var(&threshold, "threshold", 0, "fail when a watched module grows by more than this many bytes")
+	flags.Var(&watch, "watch", "modules matching regular expression to compare against the baseline, all when empty")
+	flags.BoolVar(&showPackages, "packages", false, "also report the size of every package")
+	flags.IntVar(&top, "top", 20, "number of largest entries to print, all when 0")
+	flags.BoolVar(&jsonOutput, "json", false, "print the report as JSON to stdout")
+	_ = flags.Parse(args)
+
+	if writeBaseline && baselinePath == "" {
+		fmt.Fprintln(os.Stderr, "-write-baseline requires -baseline")
+		return 2
+	}
+
+	if binary == "" {
+		pkg := "."
+		if flags.NArg() > 0 {
+			pkg = flags.Arg(0)
+		}
+
+		dir, err := os.MkdirTemp("", "check-dependency-size")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer func() { _ = os.RemoveAll(dir) }()
+
+		binary = filepath.Join(dir, "binary")
+		if err := buildBinary(binary, pkg, tags); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	report, err := AttributeSizes(binary)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if !showPackages {
+		report.Packages = nil
+	}
+
+	if writeBaseline {
+		baseline := SizeReport{Total: report.Total, Modules: report.Modules}
+		data, err := json.MarshalIndent(baseline, "", "\t")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if err := os.WriteFile(baselinePath, append(data, '\n'), 0644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	if jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	} else {
+		fmt.Printf("%10d  total\n", report.Total)
+		fmt.Println("modules:")
+		printSizes(report.Modules, top)
+		if showPackages {
+			fmt.Println("packages:")
+			printSizes(report.Packages, top)
+		}
+	}
+
+	if baselinePath == "" {
+		return 0
+	}
+
+	var baseline SizeReport
+	data, err := os.ReadFile(baselinePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid baseline %v: %v\n", baselinePath, err)
+		return 1
+	}
+
+	violations := CompareSizes(baseline, report, stringsToRegexps(watch), threshold)
+	for _, violation := range violations {
+		fmt.Fprintln(os.Stderr, violation)
+	}
+	if len(violations) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// buildBinary builds the main package into output.
+func buildBinary(output, pkg, tags string) error {
+	args := []string{"build", "-o", output}
+	if tags != "" {
+		args = append(args, "-tags", tags)
+	}
+	cmd := exec.Command("go", append(args, pkg)...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("go build %s failed: %w", pkg, err)
+	}
+	return nil
+}
+
+// AttributeSizes attributes the functions in the pclntab and the data symbols
+// of an ELF binary to packages and modules. Bytes that can't be attributed,
+// such as type descriptors and headers, are reported as "<unattributed>".
+func AttributeSizes(path string) (SizeReport, error) {
+	report := SizeReport{Modules: map[string]uint64{}, Packages: map[string]uint64{}}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return report, err
+	}
+	report.Total = uint64(stat.Size())
+
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return report, err
+	}
+	modules := []string{info.Main.Path}
+	for _, dep := range info.Deps {
+		modules = append(modules, dep.Path)
+	}
+
+	f, err := elf.Open(path)
+	if err != nil {
+		return report, err
+	}
+	defer func() { _ = f.Close() }()
+
+	text, pclntab := f.Section(".text"), f.Section(".gopclntab")
+	if text == nil || pclntab == nil {
+		return report, errors.New("binary has no .text or .gopclntab section")
+	}
+	pclndata, err := pclntab.Data()
+	if err != nil {
+		return report, err
+	}
+	table, err := gosym.NewTable(nil, gosym.NewLineTable(pclndata, text.Addr))
+	if err != nil {
+		return report, err
+	}
+	for _, fn := range table.Funcs {
+		report.Packages[fn.PackageName()] += fn.End - fn.Entry
+	}
+
+	// the symbol table is missing from stripped binaries
+	symbols, err := f.Symbols()
+	if err != nil && !errors.Is(err, elf.ErrNoSymbols) {
+		return report, err
+	}
+	for _, sym := range symbols {
+		if elf.ST_TYPE(sym.Info) != elf.STT_OBJECT || sym.Size == 0 {
+			continue
+		}
+		// zero initialized data, like .bss, takes no space in the file
+		if int(sym.Section) >= len(f.Sections) || f.Sections[sym.Section].Type == elf.SHT_NOBITS {
+			continue
+		}
+		report.Packages[symbolPackage(sym.Name)] += sym.Size
+	}
+
+	var attributed uint64
+	for pkg, size := range report.Packages {
+		if pkg == "" {
+			continue
+		}
+		report.Modules[moduleOf(pkg, info.Main.Path, modules)] += size
+		attributed += size
+	}
+	delete(report.Packages, "")
+	if attributed < report.Total {
+		report.Modules[unattributed] = report.Total - attributed
+	}
+
+	return report, nil
+}
+
+// symbolPackage returns the package of a data symbol, or an empty string for
+// compiler generated symbols.
+func symbolPackage(name string) string {
+	if strings.HasPrefix(name, "go:") || strings.HasPrefix(name, "type:") {
+		return ""
+	}
+	return (&gosym.Sym{Name: name}).PackageName()
+}
+
+// moduleOf returns the module with the longest path containing the package.
+// Standard library packages belong to "std".
+func moduleOf(pkg, main string, modules []string) string {
+	if pkg == "main" {
+		return main
+	}
+	best := ""
+	for _, module := range modules {
+		if (pkg == module || strings.HasPrefix(pkg, module+"/")) && len(module) > len(best) {
+			best = module
+		}
+	}
+	if best == "" && isStd(pkg) {
+		return "std"
+	}
+	if best == "" {
+		return unattributed
+	}
+	return best
+}
+
+// CompareSizes returns a message for every watched module that grew by more
+// than threshold bytes compared to the baseline. Modules missing from the
+// baseline grow by their whole size.
+func CompareSizes(baseline, current SizeReport, watch []*regexp.Regexp, threshold uint64) []string {
+	var names []string
+	for module := range current.Modules {
+		names = append(names, module)
+	}
+	sort.Strings(names)
+
+	var violations []string
+	for _, module := range names {
+		if len(watch) > 0 && matchesOne(module, watch) == "" {
+			continue
+		}
+		before, after := baseline.Modules[module], current.Modules[module]
+		if after > before && after-before > threshold {
+			violations = append(violations, fmt.Sprintf(
+				"module %s grew by %d bytes (%d -> %d), more than the threshold of %d bytes",
+				module, after-before, before, after, threshold))
+		}
+	}
+	return violations
+}
+
+// printSizes prints the largest entries.
+func printSizes(sizes map[string]uint64, top int) {
+	names := make([]string, 0, len(sizes))
+	for name := range sizes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, k int) bool {
+		if sizes[names[i]] != sizes[names[k]] {
+			return sizes[names[i]] > sizes[names[k]]
+		}
+		return names[i] < names[k]
+	})
+	if top > 0 && len(names) > top {
+		names = names[:top]
+	}
+	for _, name := range names {
+		fmt.Printf("%10d  %s\n", sizes[name], name)
+	}
+}
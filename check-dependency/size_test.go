@@ -0,0 +1,97 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"debug/buildinfo"
+	"os"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestAttributeSizes(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("requires an ELF binary")
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := AttributeSizes(executable)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := buildinfo.ReadFile(executable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modules := []string{info.Main.Path}
+	for _, dep := range info.Deps {
+		modules = append(modules, dep.Path)
+	}
+
+	// every module accounts for the bytes of its packages
+	var attributed uint64
+	shares := map[string]uint64{}
+	for pkg, size := range report.Packages {
+		if size == 0 || size >= report.Total {
+			t.Errorf("package %s accounts for %d of %d bytes", pkg, size, report.Total)
+		}
+		shares[moduleOf(pkg, info.Main.Path, modules)] += size
+		attributed += size
+	}
+	if attributed > report.Total {
+		t.Errorf("packages add up to %d, more than the total of %d", attributed, report.Total)
+	}
+	for module, size := range report.Modules {
+		if module != unattributed && size != shares[module] {
+			t.Errorf("module %s accounts for %d bytes, its packages for %d", module, size, shares[module])
+		}
+	}
+	if report.Modules[unattributed] != report.Total-attributed {
+		t.Errorf("%d bytes unattributed, expected %d", report.Modules[unattributed], report.Total-attributed)
+	}
+
+	if size := report.Packages["runtime"]; size == 0 || size > report.Modules["std"] {
+		t.Errorf("runtime accounts for %d of %d bytes in std", size, report.Modules["std"])
+	}
+}
+
+func TestModuleOf(t *testing.T) {
+	modules := []string{"storj.io/uplink", "storj.io/common", "storj.io/common/x"}
+	for pkg, want := range map[string]string{
+		"main":                        "storj.io/uplink",
+		"storj.io/uplink/private":     "storj.io/uplink",
+		"storj.io/common/x/y":         "storj.io/common/x",
+		"storj.io/commonx":            unattributed,
+		"runtime":                     "std",
+		"vendor/golang.org/x/net/dns": "std",
+	} {
+		if got := moduleOf(pkg, "storj.io/uplink", modules); got != want {
+			t.Errorf("moduleOf(%q) = %q, want %q", pkg, got, want)
+		}
+	}
+}
+
+func TestCompareSizes(t *testing.T) {
+	baseline := SizeReport{Modules: map[string]uint64{"std": 1000, "storj.io/common": 500, "example.com/small": 100}}
+	current := SizeReport{Modules: map[string]uint64{"std": 1200, "storj.io/common": 550, "example.com/new": 80}}
+
+	got := CompareSizes(baseline, current, nil, 50)
+	want := []string{
+		"module example.com/new grew by 80 bytes (0 -> 80), more than the threshold of 50 bytes",
+		"module std grew by 200 bytes (1000 -> 1200), more than the threshold of 50 bytes",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	got = CompareSizes(baseline, current, stringsToRegexps([]string{"^storj"}), 0)
+	if len(got) != 1 {
+		t.Errorf("unexpected violations %q", got)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"go/version"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/zeebo/errs"
+	"golang.org/x/mod/modfile"
+)
+
+// Commit message trailers that allow changes, which would otherwise be
+// reported as problems.
+const (
+	trailerDowngrade          = "Downgrade"
+	trailerDowngradeGo        = "Downgrade-Go"
+	trailerDowngradeToolchain = "Downgrade-Toolchain"
+	trailerRemoveRetract      = "Remove-Retract"
+	trailerRemoveExclude      = "Remove-Exclude"
+	trailerAddReplace         = "Add-Replace"
+)
+
+// Allowlist contains the values of the commit message trailers, keyed by the
+// trailer name.
+type Allowlist map[string]map[string]struct{}
+
+// Allows returns whether the trailer with the value is in the allowlist.
+func (allowlist Allowlist) Allows(trailer, value string) bool {
+	_, ok := allowlist[trailer][value]
+	return ok
+}
+
+// DirectiveChange is a change to a go, toolchain, retract, exclude or
+// replace directive.
+type DirectiveChange struct {
+	Kind      string `json:"kind"`
+	Directive string `json:"directive"`
+	Old       string `json:"old"`
+	New       string `json:"new"`
+}
+
+// directives are the parts of a go.mod or go.work file that are checked
+// besides the module versions.
+type directives struct {
+	goVersion string
+	toolchain string
+	retracts  []string
+	excludes  []string
+	// replaces maps the replaced module to its replacement.
+	replaces map[string]string
+}
+
+// parseDirectives parses the go.mod or go.work file. Missing files, with nil
+// data, have no directives.
+func parseDirectives(modfilePath string, data []byte) (directives, error) {
+	result := directives{replaces: map[string]string{}}
+	if data == nil {
+		return result, nil
+	}
+
+	var goStmt *modfile.Go
+	var toolchain *modfile.Toolchain
+	var replaces []*modfile.Replace
+
+	if filepath.Base(modfilePath) == "go.work" {
+		work, err := modfile.ParseWork(modfilePath, data, nil)
+		if err != nil {
+			return result, errs.Wrap(err)
+		}
+		goStmt, toolchain, replaces = work.Go, work.Toolchain, work.Replace
+	} else {
+		mod, err := modfile.Parse(modfilePath, data, nil)
+		if err != nil {
+			return result, errs.Wrap(err)
+		}
+		goStmt, toolchain, replaces = mod.Go, mod.Toolchain, mod.Replace
+
+		for _, retract := range mod.Retract {
+			result.retracts = append(result.retracts, formatInterval(retract.VersionInterval))
+		}
+		for _, exclude := range mod.Exclude {
+			result.excludes = append(result.excludes, formatVersion(exclude.Mod.Path, exclude.Mod.Version))
+		}
+	}
+
+	if goStmt != nil {
+		result.goVersion = goStmt.Version
+	}
+	if toolchain != nil {
+		result.toolchain = toolchain.Name
+	}
+	for _, replace := range replaces {
+		result.replaces[formatVersion(replace.Old.Path, replace.Old.Version)] = formatVersion(replace.New.Path, replace.New.Version)
+	}
+
+	return result, nil
+}
+
+// checkDirectives adds the go directive and toolchain downgrades, the removed
+// retract and exclude directives and the added replace directives to the file
+// report. Each of them is a problem, unless allowed by a trailer.
+func checkDirectives(file *FileReport, oldData, newData []byte, allowlist Allowlist) error {
+	oldDirectives, err := parseDirectives(file.File, oldData)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+	newDirectives, err := parseDirectives(file.File, newData)
+	if err != nil {
+		return errs.Wrap(err)
+	}
+
+	emit := func(kind, directive, oldValue, newValue, trailer, value string) {
+		file.Directives = append(file.Directives, DirectiveChange{
+			Kind:      kind,
+			Directive: directive,
+			Old:       oldValue,
+			New:       newValue,
+		})
+		if !allowlist.Allows(trailer, value) {
+			file.Problems = append(file.Problems, fmt.Sprintf(
+				"%s: %s %s was %s: if intended, add \"%s: %s\" to commit message",
+				file.File, directive, describe(oldValue, newValue), pastTense[kind], trailer, value,
+			))
+		}
+	}
+
+	if oldDirectives.goVersion != "" && newDirectives.goVersion != "" &&
+		version.Compare("go"+newDirectives.goVersion, "go"+oldDirectives.goVersion) < 0 {
+		emit("downgrade", "go", oldDirectives.goVersion, newDirectives.goVersion,
+			trailerDowngradeGo, newDirectives.goVersion)
+	}
+
+	// without a toolchain directive the go directive is the minimum toolchain,
+	// whose downgrade has been reported above.
+	oldToolchain, newToolchain := oldDirectives.effectiveToolchain(), newDirectives.effectiveToolchain()
+	if (oldDirectives.toolchain != "" || newDirectives.toolchain != "") &&
+		oldToolchain != "" && newToolchain != "" && version.Compare(newToolchain, oldToolchain) < 0 {
+		emit("downgrade", "toolchain", oldToolchain, newToolchain,
+			trailerDowngradeToolchain, newToolchain)
+	}
+
+	for _, retract := range oldDirectives.retracts {
+		if !slices.Contains(newDirectives.retracts, retract) {
+			emit("remove", "retract", retract, "none", trailerRemoveRetract, retract)
+		}
+	}
+
+	for _, exclude := range oldDirectives.excludes {
+		if !slices.Contains(newDirectives.excludes, exclude) {
+			emit("remove", "exclude", exclude, "none", trailerRemoveExclude, exclude)
+		}
+	}
+
+	for _, old := range slices.Sorted(maps.Keys(newDirectives.replaces)) {
+		replacement := newDirectives.replaces[old]
+		if previous, ok := oldDirectives.replaces[old]; ok && previous == replacement {
+			continue
+		}
+		emit("add", "replace", old, replacement, trailerAddReplace, old)
+	}
+
+	return nil
+}
+
+var pastTense = map[string]string{
+	"add":       "added",
+	"remove":    "removed",
+	"downgrade": "downgraded",
+}
+
+// effectiveToolchain returns the toolchain directive, defaulting to the go
+// directive.
+func (d directives) effectiveToolchain() string {
+	if d.toolchain == "default" {
+		return ""
+	}
+	if d.toolchain != "" {
+		return d.toolchain
+	}
+	if d.goVersion != "" {
+		return "go" + d.goVersion
+	}
+	return ""
+}
+
+// describe formats the values of a directive change for a problem message.
+func describe(oldValue, newValue string) string {
+	switch {
+	case newValue == "none":
+		return oldValue
+	case oldValue == "none":
+		return newValue
+	}
+	return oldValue + " => " + newValue
+}
+
+// formatInterval formats the retracted versions as in the go.mod file.
+func formatInterval(interval modfile.VersionInterval) string {
+	if interval.Low == interval.High {
+		return interval.Low
+	}
+	return "[" + interval.Low + ", " + interval.High + "]"
+}
+
+// formatVersion formats the module path with the optional version.
+func formatVersion(path, vers string) string {
+	return strings.TrimSpace(path + " " + vers)
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCheckDirectives(t *testing.T) {
+	oldData := []byte(`module example.com/a
+
+go 1.24.0
+
+toolchain go1.24.4
+
+retract [v1.0.0, v1.0.5]
+retract v1.1.0
+
+exclude golang.org/x/net v0.1.0
+
+replace golang.org/x/text => golang.org/x/text v0.4.0
+`)
+	newData := []byte(`module example.com/a
+
+go 1.23.0
+
+toolchain go1.24.2
+
+retract v1.1.0
+
+replace golang.org/x/text => golang.org/x/text v0.4.0
+
+replace golang.org/x/mod v0.20.0 => ../mod
+`)
+
+	file := FileReport{File: "go.mod"}
+	err := checkDirectives(&file, oldData, newData, Allowlist{
+		trailerRemoveExclude: {"golang.org/x/net v0.1.0": {}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedChanges := []DirectiveChange{
+		{Kind: "downgrade", Directive: "go", Old: "1.24.0", New: "1.23.0"},
+		{Kind: "downgrade", Directive: "toolchain", Old: "go1.24.4", New: "go1.24.2"},
+		{Kind: "remove", Directive: "retract", Old: "[v1.0.0, v1.0.5]", New: "none"},
+		{Kind: "remove", Directive: "exclude", Old: "golang.org/x/net v0.1.0", New: "none"},
+		{Kind: "add", Directive: "replace", Old: "golang.org/x/mod v0.20.0", New: "../mod"},
+	}
+	if !reflect.DeepEqual(file.Directives, expectedChanges) {
+		t.Fatalf("unexpected directives %#v", file.Directives)
+	}
+
+	expectedProblems := []string{
+		`go.mod: go 1.24.0 => 1.23.0 was downgraded: if intended, add "Downgrade-Go: 1.23.0" to commit message`,
+		`go.mod: toolchain go1.24.4 => go1.24.2 was downgraded: if intended, add "Downgrade-Toolchain: go1.24.2" to commit message`,
+		`go.mod: retract [v1.0.0, v1.0.5] was removed: if intended, add "Remove-Retract: [v1.0.0, v1.0.5]" to commit message`,
+		`go.mod: replace golang.org/x/mod v0.20.0 => ../mod was added: if intended, add "Add-Replace: golang.org/x/mod v0.20.0" to commit message`,
+	}
+	if !reflect.DeepEqual(file.Problems, expectedProblems) {
+		t.Fatalf("unexpected problems %#v", file.Problems)
+	}
+}
+
+func TestCheckDirectivesWorkspace(t *testing.T) {
+	oldData := []byte("go 1.24.0\n\nuse ./a\n")
+	newData := []byte("go 1.24.0\n\nuse ./a\n\nreplace golang.org/x/mod => ../mod\n")
+
+	file := FileReport{File: "go.work", Workspace: true}
+	err := checkDirectives(&file, oldData, newData, Allowlist{
+		trailerAddReplace: {"golang.org/x/mod": {}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []DirectiveChange{{Kind: "add", Directive: "replace", Old: "golang.org/x/mod", New: "../mod"}}
+	if !reflect.DeepEqual(file.Directives, expected) || len(file.Problems) != 0 {
+		t.Fatalf("unexpected report %#v", file)
+	}
+}
+
+func TestParseAllowlist(t *testing.T) {
+	allowlist, err := parseAllowlist([]byte("bump dependencies\n\nDowngrade: golang.org/x/mod\nDowngrade-Go: 1.23.0\nChange-Id: I0123\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Allowlist{
+		trailerDowngrade:   {"golang.org/x/mod": {}},
+		trailerDowngradeGo: {"1.23.0": {}},
+	}
+	if !reflect.DeepEqual(allowlist, expected) {
+		t.Fatalf("unexpected allowlist %v", allowlist)
+	}
+}
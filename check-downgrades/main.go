@@ -10,14 +10,13 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
-	"sync"
-	"text/tabwriter"
 
 	"github.com/zeebo/errs"
 	"golang.org/x/mod/module"
@@ -25,28 +24,37 @@ import (
 )
 
 var (
-	ref = flag.String("ref", "HEAD", "which git ref to check")
+	head   = flag.String("head", "HEAD", "git ref to check, HEAD checks the working tree")
+	base   = flag.String("base", "", "git ref to compare against, defaults to the parent of -head")
+	format = flag.String("format", "text", "output format: text, json or markdown")
 )
 
 var errDowngradesDetected = errors.New("downgrades detected")
 
 func main() {
+	flag.Func("ref", "deprecated: use -head", func(ref string) error {
+		*head = ref
+		return nil
+	})
 	flag.Parse()
 
-	if err := run(*ref); errors.Is(err, errDowngradesDetected) {
+	if *format != "text" && *format != "json" && *format != "markdown" {
+		log.Fatalf("unknown format %q", *format)
+	}
+
+	baseRef := *base
+	if baseRef == "" {
+		baseRef = *head + "^"
+	}
+
+	if err := run(baseRef, *head, *format); errors.Is(err, errDowngradesDetected) {
 		os.Exit(3)
 	} else if err != nil {
 		log.Fatalf("%+v", err)
 	}
 }
 
-func run(ref string) error {
-	olddir, err := os.MkdirTemp("", "check-downgrades-*")
-	if err != nil {
-		return errs.Wrap(err)
-	}
-	defer func() { err = errs.Combine(err, os.RemoveAll(olddir)) }()
-
+func run(baseRef, headRef, format string) (err error) {
 	gitdirBytes, err := execute(".", "git", "rev-parse", "--show-toplevel")
 	if err != nil {
 		return errs.Wrap(err)
@@ -56,44 +64,49 @@ func run(ref string) error {
 	// i'm willing to ignore it. if this fails you due to that, do better.
 	gitdir := strings.TrimRight(string(gitdirBytes), "\r\n")
 
-	_, err = execute(gitdir, "git", "worktree", "add", "-f", olddir, ref+"^")
+	olddir, removeOld, err := checkout(gitdir, baseRef)
 	if err != nil {
 		return errs.Wrap(err)
 	}
-	defer func() {
-		_, rerr := execute(gitdir, "git", "worktree", "remove", "-f", olddir)
-		err = errs.Combine(err, rerr)
-	}()
+	defer func() { err = errs.Combine(err, removeOld()) }()
+
+	// HEAD is checked in the working tree, so that uncommitted changes are
+	// included.
+	newdir := gitdir
+	if headRef != "HEAD" {
+		var removeNew func() error
+		newdir, removeNew, err = checkout(gitdir, headRef)
+		if err != nil {
+			return errs.Wrap(err)
+		}
+		defer func() { err = errs.Combine(err, removeNew()) }()
+	}
 
-	allowlist, err := getAllowlist(gitdir, ref)
+	allowlist, err := getAllowlist(gitdir, baseRef, headRef)
 	if err != nil {
 		return errs.Wrap(err)
 	}
 
-	var allProblems []string
+	var report Report
 
-	err = filepath.Walk(gitdir, func(path string, _ os.FileInfo, err error) error {
+	err = filepath.Walk(newdir, func(path string, _ os.FileInfo, err error) error {
 		if err != nil {
 			return errs.Wrap(err)
 		}
-		path, err = filepath.Rel(gitdir, path)
+		path, err = filepath.Rel(newdir, path)
 		if err != nil {
 			return errs.Wrap(err)
 		}
-		if filepath.Base(path) != "go.mod" {
+		if base := filepath.Base(path); base != "go.mod" && base != "go.work" {
 			return nil
 		}
 
-		fmt.Println("=== checking", path, "===")
-		fmt.Println()
-
-		problems, err := check(olddir, gitdir, path, allowlist)
+		file, err := check(olddir, newdir, path, allowlist)
 		if err != nil {
 			return errs.Wrap(err)
 		}
-		allProblems = append(allProblems, problems...)
-
-		fmt.Println()
+		report.Files = append(report.Files, file)
+		report.Problems = append(report.Problems, file.Problems...)
 
 		return nil
 	})
@@ -101,30 +114,56 @@ func run(ref string) error {
 		return errs.Wrap(err)
 	}
 
-	if len(allProblems) > 0 {
-		fmt.Println("=== PROBLEMS ===")
-		fmt.Println()
-
-		for _, problem := range allProblems {
-			fmt.Println("\t", problem)
-		}
+	switch format {
+	case "json":
+		err = writeJSON(os.Stdout, report)
+	case "markdown":
+		err = writeMarkdown(os.Stdout, report)
+	default:
+		err = writeText(os.Stdout, report)
+	}
+	if err != nil {
+		return errs.Wrap(err)
+	}
 
-		fmt.Println()
+	if len(report.Problems) > 0 {
 		return errDowngradesDetected
 	}
 
 	return nil
 }
 
-func check(olddir, newdir, modfile string, allowlist map[string]struct{}) (problems []string, err error) {
+// checkout adds a temporary worktree with the ref checked out.
+func checkout(gitdir, ref string) (dir string, cleanup func() error, err error) {
+	dir, err = os.MkdirTemp("", "check-downgrades-*")
+	if err != nil {
+		return "", nil, errs.Wrap(err)
+	}
+
+	_, err = execute(gitdir, "git", "worktree", "add", "-f", dir, ref)
+	if err != nil {
+		return "", nil, errs.Combine(err, os.RemoveAll(dir))
+	}
+
+	return dir, func() error {
+		_, err := execute(gitdir, "git", "worktree", "remove", "-f", dir)
+		return errs.Combine(err, os.RemoveAll(dir))
+	}, nil
+}
+
+// check compares the module versions and the directives of the go.mod or
+// go.work file between the old and the new directory.
+func check(olddir, newdir, modfile string, allowlist Allowlist) (file FileReport, err error) {
+	file = FileReport{File: modfile, Workspace: filepath.Base(modfile) == "go.work"}
+
 	oldModules, err := getModules(olddir, modfile)
 	if err != nil {
-		return nil, errs.Wrap(err)
+		return file, errs.Wrap(err)
 	}
 
 	newModules, err := getModules(newdir, modfile)
 	if err != nil {
-		return nil, errs.Wrap(err)
+		return file, errs.Wrap(err)
 	}
 
 	// get sorted list of paths
@@ -132,18 +171,20 @@ func check(olddir, newdir, modfile string, allowlist map[string]struct{}) (probl
 	for path := range oldModules {
 		paths = append(paths, path)
 	}
+	for path := range newModules {
+		if _, ok := oldModules[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
 	sort.Strings(paths)
 
-	tw := tabwriter.NewWriter(os.Stdout, 8, 4, 2, ' ', 0)
-	defer func() { err = errs.Combine(err, tw.Flush()) }()
-
-	var once sync.Once
-	emit := func(key, kind, path, oldVersion, newVersion string) {
-		once.Do(func() {
-			_, _ = fmt.Fprintf(tw, "key\tkind\tmodule\told version\tnew version\n")
-			_, _ = fmt.Fprintf(tw, "---\t----\t------\t-----------\t-----------\n")
+	emit := func(kind, path, oldVersion, newVersion string) {
+		file.Changes = append(file.Changes, Change{
+			Kind:       kind,
+			Module:     path,
+			OldVersion: oldVersion,
+			NewVersion: newVersion,
 		})
-		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", key, kind, path, oldVersion, newVersion)
 	}
 
 	// check for any unallowed downgrades
@@ -154,42 +195,65 @@ func check(olddir, newdir, modfile string, allowlist map[string]struct{}) (probl
 		if !oldOk && !newOk {
 			continue // this should never happen
 		} else if !oldOk && newOk {
-			emit("+++", "add", path, "none", newMod.Version)
+			emit("add", path, "none", newMod.Version)
 			continue
 		} else if oldOk && !newOk {
-			emit("---", "remove", path, oldMod.Version, "none")
+			emit("remove", path, oldMod.Version, "none")
 			continue
 		}
 
 		switch semver.Compare(newMod.Version, oldMod.Version) {
 		case 1: // upgrade
-			emit("^^^", "upgrade", path, oldMod.Version, newMod.Version)
+			emit("upgrade", path, oldMod.Version, newMod.Version)
 		case 0: // stable. don't print anything.
 		case -1: // downgrade
-			emit("vvv", "downgrade", path, oldMod.Version, newMod.Version)
-			if _, ok := allowlist[path]; !ok {
+			emit("downgrade", path, oldMod.Version, newMod.Version)
+			if !allowlist.Allows(trailerDowngrade, path) {
 				direct, err := directDependency(newdir, modfile, path)
 				if err != nil {
-					return nil, errs.Wrap(err)
+					return file, errs.Wrap(err)
 				}
 				if direct {
-					problems = append(problems, fmt.Sprintf(
-						"%s: %s was downgraded: if intended, add \"Downgrade: %s\" to commit message",
-						modfile, path, path,
+					file.Problems = append(file.Problems, fmt.Sprintf(
+						"%s: %s was downgraded: if intended, add \"%s: %s\" to commit message",
+						modfile, path, trailerDowngrade, path,
 					))
 				}
 			}
 		}
 	}
 
-	once.Do(func() { fmt.Println("No changes to module versions.") })
+	oldData, err := readFile(olddir, modfile)
+	if err != nil {
+		return file, errs.Wrap(err)
+	}
+	newData, err := readFile(newdir, modfile)
+	if err != nil {
+		return file, errs.Wrap(err)
+	}
+
+	return file, errs.Wrap(checkDirectives(&file, oldData, newData, allowlist))
+}
 
-	return problems, errs.Wrap(tw.Flush())
+// readFile reads the file, returning nil when it doesn't exist.
+func readFile(dir, name string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return data, errs.Wrap(err)
 }
 
 func execute(dir, bin string, args ...string) ([]byte, error) {
+	return executeEnv(dir, nil, bin, args...)
+}
+
+// executeEnv runs the command with the environment, or the current
+// environment when env is nil.
+func executeEnv(dir string, env []string, bin string, args ...string) ([]byte, error) {
 	cmd := exec.CommandContext(context.Background(), bin, args...)
 	cmd.Dir = dir
+	cmd.Env = env
 	out, err := cmd.CombinedOutput()
 	if err != nil {
 		return nil, errs.New("%w: %s", err, out)
@@ -212,21 +276,51 @@ func getModules(gitdir, modfile string) (map[string]module.Version, error) {
 	if _, err := os.Stat(filepath.Join(gitdir, modfile)); os.IsNotExist(err) {
 		return map[string]module.Version{}, nil
 	}
-	moddir, modfile := filepath.Split(filepath.Join(gitdir, modfile))
-	data, err := execute(moddir, "go", "list", "-modfile", modfile, "-m", "all")
+	data, err := goModCommand(gitdir, modfile, "list", "-m", "all")
 	if err != nil {
 		return nil, errs.Wrap(err)
 	}
 	return parseModules(data)
 }
 
+// goModCommand runs a go command, such as "mod why", for the module or
+// workspace file. A go.mod file is evaluated on its own, ignoring any
+// workspace, while a go.work file is evaluated with the build list of the
+// whole workspace.
+func goModCommand(gitdir, modfile, command string, args ...string) ([]byte, error) {
+	path := filepath.Join(gitdir, modfile)
+	moddir, name := filepath.Split(path)
+
+	if name == "go.work" {
+		// workspace mode only allows -mod=readonly, which is appended to the
+		// configured flags so that it overrides any other -mod flag
+		goflags, err := execute(moddir, "go", "env", "GOFLAGS")
+		if err != nil {
+			return nil, errs.Wrap(err)
+		}
+		env := append(os.Environ(), "GOWORK="+path, "GOFLAGS="+appendGoFlag(string(goflags), "-mod=readonly"))
+		return executeEnv(moddir, env, "go", append(strings.Fields(command), args...)...)
+	}
+
+	env := append(os.Environ(), "GOWORK=off")
+	return executeEnv(moddir, env, "go", append(append(strings.Fields(command), "-modfile", name), args...)...)
+}
+
+// appendGoFlag appends the flag to the space separated GOFLAGS value.
+func appendGoFlag(goflags, flag string) string {
+	return strings.Join(append(strings.Fields(goflags), flag), " ")
+}
+
 func parseModules(data []byte) (map[string]module.Version, error) {
 	out := make(map[string]module.Version)
+	inMain := true
 	err := foreachLine(data, func(i int, line string) error {
-		// skip the first entry
-		if i == 0 {
+		// skip the main modules, which are listed first without a version.
+		// there is a single one, unless it's a workspace.
+		if i == 0 || (inMain && !strings.Contains(line, " ")) {
 			return nil
 		}
+		inMain = false
 
 		// strip off any replace suffix
 		if index := strings.LastIndex(line, " => "); index != -1 {
@@ -258,20 +352,29 @@ func parseModules(data []byte) (map[string]module.Version, error) {
 	return out, err
 }
 
-func getAllowlist(gitdir, ref string) (map[string]struct{}, error) {
-	data, err := execute(gitdir, "git", "log", "-n", "1", "--format=%B", ref)
+// getAllowlist parses the trailers of every commit after base up to head.
+func getAllowlist(gitdir, baseRef, headRef string) (Allowlist, error) {
+	data, err := execute(gitdir, "git", "log", "--format=%B", baseRef+".."+headRef)
 	if err != nil {
 		return nil, errs.Wrap(err)
 	}
 	return parseAllowlist(data)
 }
 
-func parseAllowlist(data []byte) (map[string]struct{}, error) {
-	out := make(map[string]struct{})
+func parseAllowlist(data []byte) (Allowlist, error) {
+	out := make(Allowlist)
 	err := foreachLine(data, func(_ int, line string) error {
-		const header = "Downgrade: "
-		if strings.HasPrefix(line, header) {
-			out[line[len(header):]] = struct{}{}
+		trailer, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil
+		}
+		switch trailer {
+		case trailerDowngrade, trailerDowngradeGo, trailerDowngradeToolchain,
+			trailerRemoveRetract, trailerRemoveExclude, trailerAddReplace:
+			if out[trailer] == nil {
+				out[trailer] = make(map[string]struct{})
+			}
+			out[trailer][value] = struct{}{}
 		}
 		return nil
 	})
@@ -283,8 +386,7 @@ func directDependency(gitdir, modfile, path string) (bool, error) {
 	if _, err := os.Stat(filepath.Join(gitdir, modfile)); os.IsNotExist(err) {
 		return false, errs.New("module file missing")
 	}
-	moddir, modfile := filepath.Split(filepath.Join(gitdir, modfile))
-	data, err := execute(moddir, "go", "mod", "why", "-modfile", modfile, "-m", path)
+	data, err := goModCommand(gitdir, modfile, "mod why", "-m", path)
 	if err != nil {
 		return false, errs.Wrap(err)
 	}
@@ -4,6 +4,10 @@
 package main
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -13,3 +17,70 @@ func TestGetModules(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestParseModulesWorkspace(t *testing.T) {
+	modules, err := parseModules([]byte("example.com/a\nexample.com/b\ngolang.org/x/mod v0.20.0\ngolang.org/x/text v0.3.0 => golang.org/x/text v0.4.0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modules) != 2 || modules["golang.org/x/mod"].Version != "v0.20.0" || modules["golang.org/x/text"].Version != "v0.3.0" {
+		t.Fatalf("unexpected modules %v", modules)
+	}
+}
+
+func TestGoModCommandWorkspaceFlags(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.work":  "go 1.24.0\n\nuse ./a\n",
+		"a/go.mod": "module example.com/a\n\ngo 1.24.0\n",
+	}
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Setenv("GOFLAGS", "-tags=custom -mod=mod")
+	out, err := goModCommand(dir, "go.work", "env", "GOFLAGS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "-tags=custom -mod=mod -mod=readonly" {
+		t.Fatalf("unexpected GOFLAGS %q", got)
+	}
+
+	if _, err := goModCommand(dir, "go.work", "list -m", "all"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeMarkdown(&buf, Report{
+		Files: []FileReport{
+			{File: "go.mod", Changes: []Change{{Kind: "downgrade", Module: "golang.org/x/mod", OldVersion: "v0.20.0", NewVersion: "v0.19.0"}}},
+			{File: "go.work", Workspace: true},
+		},
+		Problems: []string{"go.mod: golang.org/x/mod was downgraded"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "## Module changes\n" +
+		"\n### `go.mod`\n\n" +
+		"| kind | module | old version | new version |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| downgrade | `golang.org/x/mod` | v0.20.0 | v0.19.0 |\n" +
+		"\n### `go.work` (workspace)\n\n" +
+		"No changes to module versions.\n" +
+		"\n### Problems\n\n" +
+		"- go.mod: golang.org/x/mod was downgraded\n"
+	if buf.String() != expected {
+		t.Fatalf("unexpected markdown:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/zeebo/errs"
+)
+
+// Report contains the changes of every module and workspace file.
+type Report struct {
+	Files    []FileReport `json:"files"`
+	Problems []string     `json:"problems"`
+}
+
+// FileReport contains the changes of a single go.mod or go.work file.
+type FileReport struct {
+	File       string            `json:"file"`
+	Workspace  bool              `json:"workspace,omitempty"`
+	Changes    []Change          `json:"changes"`
+	Directives []DirectiveChange `json:"directives,omitempty"`
+	Problems   []string          `json:"problems,omitempty"`
+}
+
+// Change is a module that was added, removed, upgraded or downgraded.
+type Change struct {
+	Kind       string `json:"kind"`
+	Module     string `json:"module"`
+	OldVersion string `json:"old_version"`
+	NewVersion string `json:"new_version"`
+}
+
+// Key returns the marker of the change kind used in the text output.
+func (change Change) Key() string {
+	return kindKey(change.Kind)
+}
+
+func kindKey(kind string) string {
+	switch kind {
+	case "add":
+		return "+++"
+	case "remove":
+		return "---"
+	case "upgrade":
+		return "^^^"
+	case "downgrade":
+		return "vvv"
+	}
+	return "???"
+}
+
+func writeText(w io.Writer, report Report) error {
+	for _, file := range report.Files {
+		_, _ = fmt.Fprintln(w, "=== checking", file.File, "===")
+		_, _ = fmt.Fprintln(w)
+
+		if len(file.Changes) == 0 {
+			_, _ = fmt.Fprintln(w, "No changes to module versions.")
+		} else {
+			tw := tabwriter.NewWriter(w, 8, 4, 2, ' ', 0)
+			_, _ = fmt.Fprintf(tw, "key\tkind\tmodule\told version\tnew version\n")
+			_, _ = fmt.Fprintf(tw, "---\t----\t------\t-----------\t-----------\n")
+			for _, change := range file.Changes {
+				_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+					change.Key(), change.Kind, change.Module, change.OldVersion, change.NewVersion)
+			}
+			if err := tw.Flush(); err != nil {
+				return errs.Wrap(err)
+			}
+		}
+
+		if len(file.Directives) > 0 {
+			_, _ = fmt.Fprintln(w)
+			tw := tabwriter.NewWriter(w, 8, 4, 2, ' ', 0)
+			_, _ = fmt.Fprintf(tw, "key\tkind\tdirective\told value\tnew value\n")
+			_, _ = fmt.Fprintf(tw, "---\t----\t---------\t---------\t---------\n")
+			for _, change := range file.Directives {
+				_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+					kindKey(change.Kind), change.Kind, change.Directive, change.Old, change.New)
+			}
+			if err := tw.Flush(); err != nil {
+				return errs.Wrap(err)
+			}
+		}
+
+		_, _ = fmt.Fprintln(w)
+	}
+
+	if len(report.Problems) > 0 {
+		_, _ = fmt.Fprintln(w, "=== PROBLEMS ===")
+		_, _ = fmt.Fprintln(w)
+
+		for _, problem := range report.Problems {
+			_, _ = fmt.Fprintln(w, "\t", problem)
+		}
+
+		_, _ = fmt.Fprintln(w)
+	}
+
+	return nil
+}
+
+func writeJSON(w io.Writer, report Report) error {
+	for i := range report.Files {
+		if report.Files[i].Changes == nil {
+			report.Files[i].Changes = []Change{}
+		}
+	}
+	if report.Problems == nil {
+		report.Problems = []string{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return errs.Wrap(enc.Encode(report))
+}
+
+// writeMarkdown writes the report in a form suitable for a review comment.
+func writeMarkdown(w io.Writer, report Report) error {
+	_, _ = fmt.Fprintln(w, "## Module changes")
+
+	for _, file := range report.Files {
+		_, _ = fmt.Fprintln(w)
+		if file.Workspace {
+			_, _ = fmt.Fprintf(w, "### `%s` (workspace)\n\n", file.File)
+		} else {
+			_, _ = fmt.Fprintf(w, "### `%s`\n\n", file.File)
+		}
+
+		if len(file.Changes) == 0 {
+			_, _ = fmt.Fprintln(w, "No changes to module versions.")
+		} else {
+			_, _ = fmt.Fprintln(w, "| kind | module | old version | new version |")
+			_, _ = fmt.Fprintln(w, "| --- | --- | --- | --- |")
+			for _, change := range file.Changes {
+				_, _ = fmt.Fprintf(w, "| %s | `%s` | %s | %s |\n",
+					change.Kind, change.Module, change.OldVersion, change.NewVersion)
+			}
+		}
+
+		if len(file.Directives) > 0 {
+			_, _ = fmt.Fprintln(w)
+			_, _ = fmt.Fprintln(w, "| kind | directive | old value | new value |")
+			_, _ = fmt.Fprintln(w, "| --- | --- | --- | --- |")
+			for _, change := range file.Directives {
+				_, _ = fmt.Fprintf(w, "| %s | %s | %s | %s |\n",
+					change.Kind, change.Directive, change.Old, change.New)
+			}
+		}
+	}
+
+	if len(report.Problems) > 0 {
+		_, _ = fmt.Fprintln(w)
+		_, _ = fmt.Fprintln(w, "### Problems")
+		_, _ = fmt.Fprintln(w)
+		for _, problem := range report.Problems {
+			_, _ = fmt.Fprintf(w, "- %s\n", strings.ReplaceAll(problem, "|", "\\|"))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package importcheck
+
+import (
+	"go/ast"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Analyzer verifies that imports are grouped, sorted and not banned.
+var Analyzer = &analysis.Analyzer{
+	Name: "checkimports",
+	Doc:  "check that imports are grouped into the configured classes, sorted and not banned",
+	Run:  run,
+}
+
+var (
+	configPath string
+	policyPath string
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&configPath, "config", "", "JSON file declaring the import classes in group order, defaults to [std other storj]")
+	Analyzer.Flags.StringVar(&policyPath, "policy", "", "JSON file listing banned imports")
+}
+
+var (
+	loadOnce       sync.Once
+	loadClassifier *Classifier
+	loadPolicy     Policy
+	loadErr        error
+)
+
+// load loads the files given by the flags.
+func load() (*Classifier, Policy, error) {
+	loadOnce.Do(func() {
+		config := DefaultConfig
+		if configPath != "" {
+			config, loadErr = LoadConfig(configPath)
+			if loadErr != nil {
+				return
+			}
+		}
+		loadClassifier, loadErr = NewClassifier(config)
+		if loadErr != nil {
+			return
+		}
+		if policyPath != "" {
+			loadPolicy, loadErr = LoadPolicy(policyPath)
+		}
+	})
+	return loadClassifier, loadPolicy, loadErr
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	classifier, policy, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range pass.Files {
+		if ast.IsGenerated(file) {
+			continue
+		}
+
+		name := pass.Fset.File(file.Pos()).Name()
+		src, err := pass.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		violations := policy.Check(pass.Fset, file, name, pass.Pkg.Path())
+		violations = append(violations, FileViolations(classifier, pass.Fset, file, src, LoadImports(pass.Fset, name, file))...)
+
+		for _, v := range violations {
+			diag := analysis.Diagnostic{
+				Pos:     v.pos,
+				Message: v.Message,
+			}
+			if v.fix != nil {
+				diag.SuggestedFixes = []analysis.SuggestedFix{{
+					Message:   "Group and sort imports",
+					TextEdits: []analysis.TextEdit{*v.fix},
+				}}
+			}
+			pass.Report(diag)
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,197 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package importcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Config declares the import classes in the order their groups must appear.
+type Config struct {
+	Classes []ClassConfig `json:"classes"`
+}
+
+// ClassConfig declares a single import class.
+//
+// An import belongs to the class with the longest matching prefix or regular
+// expression match. Imports that don't match any of them belong to the std
+// class when they are standard library packages, otherwise to the default
+// class.
+type ClassConfig struct {
+	Name     string   `json:"name"`
+	Std      bool     `json:"std,omitempty"`
+	Default  bool     `json:"default,omitempty"`
+	Prefixes []string `json:"prefixes,omitempty"`
+	Regexps  []string `json:"regexps,omitempty"`
+}
+
+// DefaultConfig is the standard grouping [std other storj].
+var DefaultConfig = Config{
+	Classes: []ClassConfig{
+		{Name: "std", Std: true},
+		{Name: "other", Default: true},
+		{Name: "storj", Prefixes: []string{"storj.io"}},
+	},
+}
+
+// Classifier classifies imports into the configured classes.
+type Classifier struct {
+	names    []string
+	std      int
+	def      int
+	prefixes [][]string
+	regexps  [][]*regexp.Regexp
+}
+
+// LoadConfig loads a JSON configuration file.
+func LoadConfig(path string) (Config, error) {
+	var config Config
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("invalid config %v: %w", path, err)
+	}
+	return config, nil
+}
+
+// NewClassifier verifies the configuration and creates a classifier for it.
+func NewClassifier(config Config) (*Classifier, error) {
+	if len(config.Classes) == 0 || len(config.Classes) > 8 {
+		return nil, fmt.Errorf("expected 1 to 8 classes, got %d", len(config.Classes))
+	}
+
+	c := &Classifier{std: -1, def: -1}
+	for i, class := range config.Classes {
+		if class.Name == "" {
+			return nil, fmt.Errorf("class %d has no name", i+1)
+		}
+		c.names = append(c.names, class.Name)
+
+		if class.Std {
+			if c.std >= 0 {
+				return nil, fmt.Errorf("classes %q and %q are both std", c.names[c.std], class.Name)
+			}
+			c.std = i
+		}
+		if class.Default {
+			if c.def >= 0 {
+				return nil, fmt.Errorf("classes %q and %q are both default", c.names[c.def], class.Name)
+			}
+			c.def = i
+		}
+
+		var prefixes []string
+		for _, prefix := range class.Prefixes {
+			prefixes = append(prefixes, strings.TrimSuffix(prefix, "/"))
+		}
+		c.prefixes = append(c.prefixes, prefixes)
+
+		var regexps []*regexp.Regexp
+		for _, expr := range class.Regexps {
+			rx, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, fmt.Errorf("class %q: %w", class.Name, err)
+			}
+			regexps = append(regexps, rx)
+		}
+		c.regexps = append(c.regexps, regexps)
+	}
+
+	if c.def < 0 {
+		return nil, fmt.Errorf("no default class")
+	}
+	return c, nil
+}
+
+// MustNewClassifier is like NewClassifier, but panics on an invalid configuration.
+func MustNewClassifier(config Config) *Classifier {
+	c, err := NewClassifier(config)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Classify classifies an import path to a class.
+func (c *Classifier) Classify(pkgPath string) Class {
+	best, bestLen := -1, -1
+	for i := range c.names {
+		for _, prefix := range c.prefixes[i] {
+			if (pkgPath == prefix || strings.HasPrefix(pkgPath, prefix+"/")) && len(prefix) > bestLen {
+				best, bestLen = i, len(prefix)
+			}
+		}
+		for _, rx := range c.regexps[i] {
+			if loc := rx.FindStringIndex(pkgPath); loc != nil && loc[1]-loc[0] > bestLen {
+				best, bestLen = i, loc[1]-loc[0]
+			}
+		}
+	}
+	if best >= 0 {
+		return Class(1 << best)
+	}
+
+	if c.std >= 0 && isStandard(pkgPath) {
+		return Class(1 << c.std)
+	}
+	return Class(1 << c.def)
+}
+
+// Names returns the class names in the group order.
+func (c *Classifier) Names() []string {
+	return c.names
+}
+
+// isStandard returns whether the import path looks like a standard library package.
+func isStandard(pkgPath string) bool {
+	// https://github.com/golang/go/blob/master/src/cmd/go/internal/search/search.go#L554
+	i := strings.Index(pkgPath, "/")
+	if i < 0 {
+		i = len(pkgPath)
+	}
+	return !strings.Contains(pkgPath[:i], ".")
+}
+
+// Class defines a bitset of import classification, where each bit is the
+// index of a configured class.
+type Class byte
+
+// Classes of the default configuration.
+const (
+	// Standard is all go standard packages.
+	Standard Class = 1 << iota
+	// Other is everything else.
+	Other
+	// Storj is imports that start with `storj.io`.
+	Storj
+)
+
+// Order returns the position of a single class in the group order.
+func (class Class) Order() int {
+	return bits.TrailingZeros8(uint8(class))
+}
+
+// IsSingle returns whether the bitset contains exactly one class.
+func (class Class) IsSingle() bool {
+	return bits.OnesCount8(uint8(class)) == 1
+}
+
+// Format returns the names of the classes in the bitset.
+func (c *Classifier) Format(class Class) string {
+	var s []string
+	for i, name := range c.names {
+		if class&(1<<i) != 0 {
+			s = append(s, name)
+		}
+	}
+	return strings.Join(s, "|")
+}
@@ -0,0 +1,50 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package importcheck
+
+import "testing"
+
+func TestClassifier(t *testing.T) {
+	c := MustNewClassifier(Config{
+		Classes: []ClassConfig{
+			{Name: "std", Std: true},
+			{Name: "other", Default: true},
+			{Name: "storj", Prefixes: []string{"storj.io/", "github.com/storj"}},
+			{Name: "local", Regexps: []string{`^storj\.io/edge(/|$)`}},
+		},
+	})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"fmt", "std"},
+		{"net/http", "std"},
+		{"github.com/zeebo/errs", "other"},
+		{"storj.io.example.com/x", "other"},
+		{"storj.io/common/memory", "storj"},
+		{"github.com/storj/ci", "storj"},
+		{"storj.io/edge", "local"},
+		{"storj.io/edge/pkg/auth", "local"},
+	}
+	for _, tc := range tests {
+		class := c.Classify(tc.path)
+		if got := c.Names()[class.Order()]; got != tc.want {
+			t.Errorf("Classify(%q) = %q, want %q", tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestNewClassifierErrors(t *testing.T) {
+	for _, config := range []Config{
+		{},
+		{Classes: []ClassConfig{{Name: "std", Std: true}}},
+		{Classes: []ClassConfig{{Name: "a", Default: true}, {Name: "b", Default: true}}},
+		{Classes: []ClassConfig{{Name: "a", Default: true}, {Name: "b", Regexps: []string{"("}}}},
+	} {
+		if _, err := NewClassifier(config); err == nil {
+			t.Errorf("expected error for %+v", config)
+		}
+	}
+}
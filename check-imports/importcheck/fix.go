@@ -0,0 +1,115 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package importcheck
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+)
+
+// FixImports returns src with the specs of every import block regrouped into
+// the grouping of the classifier and sorted. Doc and line comments, names, blank and dot
+// imports are kept with their specs. The result is formatted with go/format.
+//
+// file must be parsed from src with comments.
+func FixImports(c *Classifier, fset *token.FileSet, file *ast.File, src []byte) ([]byte, error) {
+	var out bytes.Buffer
+	last := 0
+	for _, decl := range importBlocks(file) {
+		start := fset.Position(decl.Lparen).Offset + 1
+		end := fset.Position(decl.Rparen).Offset
+
+		out.Write(src[last:start])
+		out.Write(fixBlock(c, fset, decl, src))
+		last = end
+	}
+	out.Write(src[last:])
+
+	return format.Source(out.Bytes())
+}
+
+// importBlocks returns the parenthesized import declarations of file, in the
+// same order as LoadImports returns them.
+func importBlocks(file *ast.File) []*ast.GenDecl {
+	var blocks []*ast.GenDecl
+	for _, d := range file.Decls {
+		d, ok := d.(*ast.GenDecl)
+		if !ok || d.Tok != token.IMPORT {
+			break
+		}
+		if d.Lparen.IsValid() {
+			blocks = append(blocks, d)
+		}
+	}
+	return blocks
+}
+
+// importChunk is the source of a single import spec, including the comments
+// that precede it and its line comment.
+type importChunk struct {
+	path  string
+	class Class
+	text  []byte
+}
+
+// fixBlock returns the new contents of the import block between the
+// parentheses, indented as gofmt would for gofmt-ed input.
+func fixBlock(c *Classifier, fset *token.FileSet, decl *ast.GenDecl, src []byte) []byte {
+	var chunks []importChunk
+
+	// Every chunk starts where the previous one ended, so comments between
+	// specs move together with the spec that follows them.
+	start := fset.Position(decl.Lparen).Offset + 1
+	for _, spec := range decl.Specs {
+		spec := spec.(*ast.ImportSpec)
+
+		endPos := spec.End()
+		if spec.Comment != nil {
+			endPos = spec.Comment.End()
+		}
+		end := fset.Position(endPos).Offset
+
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			panic(err)
+		}
+
+		chunks = append(chunks, importChunk{
+			path:  path,
+			class: c.Classify(path),
+			text:  bytes.TrimSpace(src[start:end]),
+		})
+		start = end
+	}
+
+	sort.SliceStable(chunks, func(i, k int) bool {
+		if chunks[i].class != chunks[k].class {
+			return chunks[i].class.Order() < chunks[k].class.Order()
+		}
+		return chunks[i].path < chunks[k].path
+	})
+
+	var out bytes.Buffer
+	out.WriteString("\n")
+	for i, chunk := range chunks {
+		if i > 0 && chunks[i-1].class != chunk.class {
+			out.WriteString("\n")
+		}
+		out.WriteString("\t")
+		out.Write(chunk.text)
+		out.WriteString("\n")
+	}
+
+	// Comments after the last spec stay at the end of the block.
+	if trailing := bytes.TrimSpace(src[start:fset.Position(decl.Rparen).Offset]); len(trailing) > 0 {
+		out.WriteString("\t")
+		out.Write(trailing)
+		out.WriteString("\n")
+	}
+	return out.Bytes()
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package importcheck
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestFixImports(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "regroup and sort",
+			in: `package a
+
+import (
+	"storj.io/common/memory"
+	"fmt"
+
+	"github.com/zeebo/errs"
+	"bytes"
+)
+`,
+			want: `package a
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/common/memory"
+)
+`,
+		},
+		{
+			name: "keep comments and names",
+			in: `package a
+
+import (
+	// memory sizes
+	"storj.io/common/memory" // size
+	. "os"
+	_ "embed"
+	errs2 "github.com/zeebo/errs"
+	// trailing
+)
+`,
+			want: `package a
+
+import (
+	_ "embed"
+	. "os"
+
+	errs2 "github.com/zeebo/errs"
+
+	// memory sizes
+	"storj.io/common/memory" // size
+	// trailing
+)
+`,
+		},
+		{
+			name: "single imports untouched",
+			in: `package a
+
+import "C"
+
+import (
+	"os"
+	"fmt"
+)
+`,
+			want: `package a
+
+import "C"
+
+import (
+	"fmt"
+	"os"
+)
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "a.go", tc.in, parser.ImportsOnly|parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := FixImports(MustNewClassifier(DefaultConfig), fset, file, []byte(tc.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2019 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package importcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strconv"
+)
+
+// Imports defines all imports for a single file.
+type Imports struct {
+	Path      string
+	Generated bool
+	Decls     []ImportDecl
+}
+
+// Classes returns all import groupings.
+func (imports Imports) Classes(c *Classifier) [][]Class {
+	var classes [][]Class
+	for _, decl := range imports.Decls {
+		classes = append(classes, decl.Classes(c))
+	}
+	return classes
+}
+
+// GroupingError describes the first grouping violation in the file.
+func (imports Imports) GroupingError(c *Classifier) string {
+	for _, decl := range imports.Decls {
+		if err := decl.GroupingError(c); err != "" {
+			return err
+		}
+	}
+	return ""
+}
+
+// ImportDecl defines a single import declaration.
+type ImportDecl []ImportGroup
+
+// IsGrouped returns whether each group contains a single class and the groups
+// follow the configured class order.
+func (decls ImportDecl) IsGrouped(c *Classifier) bool {
+	return decls.GroupingError(c) == ""
+}
+
+// GroupingError describes how the grouping violates the configured class
+// order, or returns an empty string when it doesn't.
+func (decls ImportDecl) GroupingError(c *Classifier) string {
+	classes := decls.Classes(c)
+	for i, class := range classes {
+		if !class.IsSingle() {
+			return fmt.Sprintf("group %d mixes %s", i+1, c.Format(class))
+		}
+		if i > 0 && classes[i-1].Order() >= class.Order() {
+			if classes[i-1] == class {
+				return fmt.Sprintf("%s is split into multiple groups", c.Format(class))
+			}
+			return fmt.Sprintf("%s must come before %s", c.Format(class), c.Format(classes[i-1]))
+		}
+	}
+	return ""
+}
+
+// Classes returns each group class.
+func (decls ImportDecl) Classes(c *Classifier) []Class {
+	classes := make([]Class, len(decls))
+	for i := range classes {
+		classes[i] = decls[i].Class(c)
+	}
+	return classes
+}
+
+// IsSorted returns whether the group is sorted.
+func (decls ImportDecl) IsSorted() bool {
+	for _, decl := range decls {
+		if !decl.IsSorted() {
+			return false
+		}
+	}
+	return true
+}
+
+// ImportGroup defines a single import statement.
+type ImportGroup struct {
+	Specs []*ast.ImportSpec
+	Paths []string
+}
+
+// IsSorted returns whether the group is sorted.
+func (group ImportGroup) IsSorted() bool {
+	return sort.StringsAreSorted(group.Paths)
+}
+
+// Class returns the classification of this import group.
+func (group ImportGroup) Class(c *Classifier) Class {
+	var class Class
+	for _, path := range group.Paths {
+		class |= c.Classify(path)
+	}
+	return class
+}
+
+// LoadImports loads import groups from a given fileset.
+func LoadImports(fset *token.FileSet, name string, f *ast.File) Imports {
+	var imports Imports
+	imports.Path = name
+
+	for _, d := range f.Decls {
+		d, ok := d.(*ast.GenDecl)
+		if !ok || d.Tok != token.IMPORT {
+			// Not an import declaration, so we're done.
+			// Imports are always first.
+			break
+		}
+
+		if !d.Lparen.IsValid() {
+			// Not a block: sorted by default.
+			continue
+		}
+
+		// identify specs on successive lines
+		lastGroup := 0
+		specgroups := [][]ast.Spec{}
+		for i, s := range d.Specs {
+			if i > lastGroup && fset.Position(s.Pos()).Line > 1+fset.Position(d.Specs[i-1].End()).Line {
+				// i begins a new run. End this one.
+				specgroups = append(specgroups, d.Specs[lastGroup:i])
+				lastGroup = i
+			}
+		}
+		specgroups = append(specgroups, d.Specs[lastGroup:])
+
+		// convert ast.Spec-s groups into import groups
+		var decl ImportDecl
+		for _, specgroup := range specgroups {
+			var group ImportGroup
+			for _, importSpec := range specgroup {
+				importSpec := importSpec.(*ast.ImportSpec)
+				path, err := strconv.Unquote(importSpec.Path.Value)
+				if err != nil {
+					panic(err)
+				}
+				group.Specs = append(group.Specs, importSpec)
+				group.Paths = append(group.Paths, path)
+			}
+			decl = append(decl, group)
+		}
+
+		imports.Decls = append(imports.Decls, decl)
+	}
+
+	return imports
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package importcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Policy declares imports that must not be used.
+type Policy struct {
+	Banned []BannedImport `json:"banned"`
+}
+
+// BannedImport is a forbidden import path with the message suggesting a
+// replacement.
+//
+// Path and Allow use package patterns: either an exact path or a path ending
+// with "/...", which matches the path and everything below it.
+type BannedImport struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+	// Allow lists the patterns of importing packages that may still use the path.
+	Allow []string `json:"allow,omitempty"`
+}
+
+// LoadPolicy loads a JSON import policy file.
+func LoadPolicy(path string) (Policy, error) {
+	var policy Policy
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return policy, err
+	}
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return policy, fmt.Errorf("invalid policy %v: %w", path, err)
+	}
+	for i, banned := range policy.Banned {
+		if banned.Path == "" {
+			return policy, fmt.Errorf("invalid policy %v: rule %d has no path", path, i+1)
+		}
+	}
+	return policy, nil
+}
+
+// Check returns a violation for every import in file that's banned for pkgPath.
+func (policy Policy) Check(fset *token.FileSet, file *ast.File, name, pkgPath string) []Violation {
+	var violations []Violation
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			panic(err)
+		}
+
+		banned, ok := policy.find(path, pkgPath)
+		if !ok {
+			continue
+		}
+
+		message := fmt.Sprintf("import %q is banned", path)
+		if banned.Message != "" {
+			message += ": " + banned.Message
+		}
+
+		pos := fset.Position(spec.Pos())
+		violations = append(violations, Violation{
+			File:    name,
+			Line:    pos.Line,
+			Column:  pos.Column,
+			Import:  path,
+			Banned:  true,
+			Message: message,
+			pos:     spec.Pos(),
+		})
+	}
+	return violations
+}
+
+// find returns the rule that bans importing path from pkgPath.
+func (policy Policy) find(path, pkgPath string) (BannedImport, bool) {
+	for _, banned := range policy.Banned {
+		if !matchPattern(banned.Path, path) {
+			continue
+		}
+
+		allowed := false
+		for _, allow := range banned.Allow {
+			if matchPattern(allow, pkgPath) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return banned, true
+		}
+	}
+	return BannedImport{}, false
+}
+
+// matchPattern returns whether path matches the package pattern.
+func matchPattern(pattern, path string) bool {
+	if pattern == "..." {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		return path == prefix || strings.HasPrefix(path, prefix+"/")
+	}
+	return path == pattern
+}
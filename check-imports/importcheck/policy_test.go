@@ -0,0 +1,56 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package importcheck
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestPolicy(t *testing.T) {
+	policy := Policy{Banned: []BannedImport{
+		{Path: "github.com/pkg/errors", Message: "use github.com/zeebo/errs"},
+		{Path: "io/ioutil", Message: "use os or io", Allow: []string{"storj.io/storj/private/migrate/..."}},
+		{Path: "gopkg.in/spacemonkeygo/monkit.v2/...", Message: "use github.com/spacemonkeygo/monkit/v3"},
+	}}
+
+	src := `package a
+
+import (
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"github.com/pkg/errorsx"
+	"gopkg.in/spacemonkeygo/monkit.v2/environment"
+)
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "a.go", src, parser.ImportsOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, v := range policy.Check(fset, file, "a.go", "storj.io/storj/satellite") {
+		got = append(got, v.String())
+	}
+	want := []string{
+		`a.go:4:2: import "io/ioutil" is banned: use os or io`,
+		`a.go:6:2: import "github.com/pkg/errors" is banned: use github.com/zeebo/errs`,
+		`a.go:8:2: import "gopkg.in/spacemonkeygo/monkit.v2/environment" is banned: use github.com/spacemonkeygo/monkit/v3`,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("got %q, want %q", got[i], want[i])
+		}
+	}
+
+	if violations := policy.Check(fset, file, "a.go", "storj.io/storj/private/migrate/v2"); len(violations) != 2 {
+		t.Errorf("expected io/ioutil to be allowed, got %v", violations)
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package a
+
+import (
+	"storj.io/common/memory" // sizes
+	"fmt"                    // want `import "fmt" belongs to group std: group 1 mixes std\|storj`
+
+	"github.com/zeebo/errs"
+	"bytes"
+)
+
+var (
+	_ = memory.Size(0)
+	_ = fmt.Sprint
+	_ = errs.Class("")
+	_ = bytes.NewReader
+)
@@ -0,0 +1,14 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package a
+
+import (
+	"fmt"
+	"bytes" // want `import "bytes" is not sorted within group std`
+)
+
+var (
+	_ = fmt.Sprint
+	_ = bytes.NewReader
+)
@@ -0,0 +1,8 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package a
+
+import "io/ioutil" // want `import "io/ioutil" is banned: use os or io`
+
+var _ = ioutil.Discard
@@ -0,0 +1,8 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package errs is a minimal stub of github.com/zeebo/errs for tests.
+package errs
+
+// Class is an error class.
+type Class string
@@ -0,0 +1,8 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package memory is a minimal stub of storj.io/common/memory for tests.
+package memory
+
+// Size is a memory size.
+type Size int64
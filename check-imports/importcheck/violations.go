@@ -0,0 +1,181 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package importcheck
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// Violation describes a misgrouped or unsorted import declaration, or a banned
+// import.
+type Violation struct {
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	Column     int    `json:"column"`
+	Import     string `json:"import"`
+	Group      string `json:"expected_group,omitempty"`
+	Misgrouped bool   `json:"misgrouped"`
+	Unsorted   bool   `json:"unsorted"`
+	Banned     bool   `json:"banned"`
+	Message    string `json:"message"`
+	Diff       string `json:"diff,omitempty"`
+
+	// pos is the position of the offending import spec.
+	pos token.Pos
+	// fix rewrites the import declaration, when it can be fixed.
+	fix *analysis.TextEdit
+}
+
+// String formats the violation as file:line:col: message.
+func (v Violation) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", v.File, v.Line, v.Column, v.Message)
+}
+
+// FileViolations returns a violation for every import declaration in file that
+// is not grouped or sorted. The violation points at the first offending import
+// spec and contains a diff of the corrected declaration.
+func FileViolations(c *Classifier, fset *token.FileSet, file *ast.File, src []byte, imports Imports) []Violation {
+	var violations []Violation
+
+	for i, decl := range importBlocks(file) {
+		if i >= len(imports.Decls) {
+			break
+		}
+		groups := imports.Decls[i]
+
+		v := Violation{
+			Misgrouped: !groups.IsGrouped(c),
+			Unsorted:   !groups.IsSorted(),
+		}
+		if !v.Misgrouped && !v.Unsorted {
+			continue
+		}
+
+		var spec *ast.ImportSpec
+		if v.Misgrouped {
+			spec, v.Import = groups.firstMisgrouped(c)
+			v.Group = c.Format(c.Classify(v.Import))
+			v.Message = fmt.Sprintf("import %q belongs to group %s: %s", v.Import, v.Group, groups.GroupingError(c))
+		} else {
+			spec, v.Import = groups.firstUnsorted()
+			v.Group = c.Format(c.Classify(v.Import))
+			v.Message = fmt.Sprintf("import %q is not sorted within group %s", v.Import, v.Group)
+		}
+
+		pos := fset.Position(spec.Pos())
+		v.File, v.Line, v.Column = imports.Path, pos.Line, pos.Column
+		v.Diff = declDiff(c, fset, decl, src, imports.Path)
+		v.pos = spec.Pos()
+		v.fix = &analysis.TextEdit{
+			Pos:     decl.Lparen + 1,
+			End:     decl.Rparen,
+			NewText: fixBlock(c, fset, decl, src),
+		}
+
+		violations = append(violations, v)
+	}
+
+	return violations
+}
+
+// firstMisgrouped returns the first import that breaks the configured grouping.
+func (decls ImportDecl) firstMisgrouped(c *Classifier) (*ast.ImportSpec, string) {
+	var seen, prev Class
+	for _, group := range decls {
+		var groupClass Class
+		for i, path := range group.Paths {
+			class := c.Classify(path)
+			if i == 0 {
+				if class&seen != 0 || (prev != 0 && class.Order() < prev.Order()) {
+					return group.Specs[i], path
+				}
+				groupClass = class
+			} else if class != groupClass {
+				return group.Specs[i], path
+			}
+		}
+		seen |= groupClass
+		prev = groupClass
+	}
+	// Unreachable for misgrouped declarations.
+	return decls[0].Specs[0], decls[0].Paths[0]
+}
+
+// firstUnsorted returns the first import that is out of order in its group.
+func (decls ImportDecl) firstUnsorted() (*ast.ImportSpec, string) {
+	for _, group := range decls {
+		for i := 1; i < len(group.Paths); i++ {
+			if group.Paths[i] < group.Paths[i-1] {
+				return group.Specs[i], group.Paths[i]
+			}
+		}
+	}
+	// Unreachable for unsorted declarations.
+	return decls[0].Specs[0], decls[0].Paths[0]
+}
+
+// declDiff returns a unified diff between the import declaration and its
+// corrected version.
+func declDiff(c *Classifier, fset *token.FileSet, decl *ast.GenDecl, src []byte, name string) string {
+	before := string(src[fset.Position(decl.Pos()).Offset:fset.Position(decl.End()).Offset])
+
+	var fixed bytes.Buffer
+	fixed.WriteString("import (")
+	fixed.Write(fixBlock(c, fset, decl, src))
+	fixed.WriteString(")")
+	after, err := format.Source(fixed.Bytes())
+	if err != nil {
+		return ""
+	}
+
+	return unifiedDiff(name, fset.Position(decl.Pos()).Line, before, string(after))
+}
+
+// unifiedDiff returns a unified diff with a single hunk between before and after,
+// which start at line in the file.
+func unifiedDiff(name string, line int, before, after string) string {
+	a := strings.Split(strings.TrimSuffix(before, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(after, "\n"), "\n")
+
+	// lcs[i][k] is the length of the longest common subsequence of a[i:] and b[k:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for k := len(b) - 1; k >= 0; k-- {
+			if a[i] == b[k] {
+				lcs[i][k] = lcs[i+1][k+1] + 1
+			} else {
+				lcs[i][k] = max(lcs[i+1][k], lcs[i][k+1])
+			}
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n@@ -%d,%d +%d,%d @@\n", name, name, line, len(a), line, len(b))
+	i, k := 0, 0
+	for i < len(a) || k < len(b) {
+		switch {
+		case i < len(a) && k < len(b) && a[i] == b[k]:
+			out.WriteString(" " + a[i] + "\n")
+			i++
+			k++
+		case k < len(b) && (i == len(a) || lcs[i][k+1] >= lcs[i+1][k]):
+			out.WriteString("+" + b[k] + "\n")
+			k++
+		default:
+			out.WriteString("-" + a[i] + "\n")
+			i++
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package importcheck
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestFileViolations(t *testing.T) {
+	src := `package a
+
+import (
+	"storj.io/common/memory"
+	"os"
+	"fmt"
+)
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "a.go", src, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	violations := FileViolations(MustNewClassifier(DefaultConfig), fset, file, []byte(src), LoadImports(fset, "a.go", file))
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 violation, got %v", violations)
+	}
+
+	v := violations[0]
+	if got, want := v.String(), `a.go:5:2: import "os" belongs to group std: group 1 mixes std|storj`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if !v.Misgrouped || !v.Unsorted || v.Group != "std" {
+		t.Errorf("unexpected violation %+v", v)
+	}
+
+	wantDiff := `--- a.go
++++ a.go
+@@ -3,5 +3,6 @@
+ import (
++	"fmt"
++	"os"
++
+ 	"storj.io/common/memory"
+-	"os"
+-	"fmt"
+ )
+`
+	if v.Diff != wantDiff {
+		t.Errorf("got diff:\n%s\nwant:\n%s", v.Diff, wantDiff)
+	}
+}
+
+func TestFileViolationsClassifier(t *testing.T) {
+	src := `package a
+
+import (
+	"fmt"
+
+	"github.com/zeebo/errs"
+	"storj.io/common/memory"
+)
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "a.go", src, parser.ImportsOnly|parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imports := LoadImports(fset, "a.go", file)
+
+	// without a storj class, storj.io belongs to the external packages
+	if violations := FileViolations(MustNewClassifier(Config{
+		Classes: []ClassConfig{
+			{Name: "std", Std: true},
+			{Name: "other", Default: true},
+		},
+	}), fset, file, []byte(src), imports); len(violations) != 0 {
+		t.Fatalf("expected no violations, got %v", violations)
+	}
+
+	violations := FileViolations(MustNewClassifier(DefaultConfig), fset, file, []byte(src), imports)
+	if len(violations) != 1 {
+		t.Fatalf("expected 1 violation, got %v", violations)
+	}
+	if got, want := violations[0].Message, `import "storj.io/common/memory" belongs to group storj: group 2 mixes other|storj`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -5,7 +5,7 @@ package main
 
 import (
 	"bytes"
-	"errors"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"go/ast"
@@ -13,11 +13,11 @@ import (
 	"go/token"
 	"io"
 	"os"
-	"reflect"
 	"sort"
-	"strconv"
 	"strings"
 
+	"github.com/storj/ci/check-imports/importcheck"
+	"golang.org/x/tools/go/analysis/unitchecker"
 	"golang.org/x/tools/go/packages"
 )
 
@@ -28,15 +28,85 @@ check-imports verifies whether imports are divided into three blocks:
 	external packages
 	storj.io packages
 
+The blocks can be configured with -config, which takes a JSON file declaring
+the import classes in the order their groups must appear, e.g.:
+
+	{"classes": [
+		{"name": "std", "std": true},
+		{"name": "other", "default": true},
+		{"name": "storj", "prefixes": ["storj.io", "github.com/storj"]},
+		{"name": "local", "regexps": ["^storj\\.io/edge(/|$)"]}
+	]}
+
+An import belongs to the class with the longest matching prefix or regular
+expression match; otherwise to the "std" class when it's a standard library
+package and to the "default" class when it's not.
+
+The -policy flag takes a JSON file listing banned imports, with a message
+suggesting the replacement and the patterns of packages that may still use them:
+
+	{"banned": [
+		{"path": "github.com/pkg/errors", "message": "use github.com/zeebo/errs"},
+		{"path": "io/ioutil", "message": "use os or io", "allow": ["storj.io/storj/private/migrate/..."]},
+		{"path": "gopkg.in/spacemonkeygo/monkit.v2/...", "message": "use github.com/spacemonkeygo/monkit/v3"}
+	]}
+
+Patterns are either exact paths or paths ending with "/...", which match the
+path and everything below it.
+
+Each violation is reported at the position of the first offending import spec,
+together with a diff of the corrected import declaration. With -json they are
+printed as JSON to stdout instead.
+
+The check is also available as an analysis.Analyzer in the importcheck package,
+for use in a multichecker or gopls, and the binary can be used with go vet:
+
+	go vet -vettool=$(which check-imports) -checkimports.policy=policy.json ./...
+
+With -fix the import blocks of the offending files are rewritten into the
+grouping and sorted, keeping comments and named imports with their specs.
+
 */
 
 func main() {
+	if isVetTool(os.Args[1:]) {
+		unitchecker.Main(importcheck.Analyzer)
+	}
+
 	race := flag.Bool("race", false, "load with race tag")
 	withdeps := flag.Bool("deps", false, "include deps in analysis")
 	depprefix := flag.String("depprefix", "storj.io", "verify only deps with this prefix")
+	fix := flag.Bool("fix", false, "rewrite misgrouped and unsorted imports")
+	jsonOutput := flag.Bool("json", false, "print the violations as JSON to stdout")
+	policyPath := flag.String("policy", "", "JSON file listing banned imports")
+	configPath := flag.String("config", "", "JSON file declaring the import classes in group order, defaults to [std other storj]")
 
 	flag.Parse()
 
+	classifier := importcheck.MustNewClassifier(importcheck.DefaultConfig)
+	if *configPath != "" {
+		config, err := importcheck.LoadConfig(*configPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		classifier, err = importcheck.NewClassifier(config)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid config %v: %v\n", *configPath, err)
+			os.Exit(1)
+		}
+	}
+
+	var policy importcheck.Policy
+	if *policyPath != "" {
+		var err error
+		policy, err = importcheck.LoadPolicy(*policyPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
 	pkgNames := flag.Args()
 	if len(pkgNames) == 0 {
 		pkgNames = []string{"."}
@@ -84,62 +154,79 @@ func main() {
 	// sort the packages
 	sort.Slice(pkgs, func(i, k int) bool { return pkgs[i].ID < pkgs[k].ID })
 
-	var misgrouped, unsorted []Imports
+	var violations []importcheck.Violation
 	for _, pkg := range pkgs {
-		pkgmisgrouped, pkgunsorted := verifyPackage(os.Stderr, pkg)
-
-		misgrouped = append(misgrouped, pkgmisgrouped...)
-		unsorted = append(unsorted, pkgunsorted...)
+		violations = append(violations, verifyPackage(os.Stderr, pkg, classifier, policy, *fix)...)
 	}
 
 	exitCode := 0
-	if len(misgrouped) > 0 {
+	if len(violations) > 0 {
 		exitCode = 1
-
-		fmt.Fprintln(os.Stderr)
-		fmt.Fprintln(os.Stderr, "Imports are not in the standard grouping [std other storj]:")
-		for _, imports := range misgrouped {
-			fmt.Fprintln(os.Stderr, "\t"+imports.Path, imports.Classes())
-		}
 	}
 
-	if len(unsorted) > 0 {
-		exitCode = 1
-
-		fmt.Fprintln(os.Stderr)
-		fmt.Fprintln(os.Stderr, "Imports are not sorted:")
-		for _, imports := range unsorted {
-			fmt.Fprintln(os.Stderr, "\t"+imports.Path)
+	if *jsonOutput {
+		if violations == nil {
+			violations = []importcheck.Violation{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		if err := enc.Encode(violations); err != nil {
+			panic(err)
+		}
+	} else {
+		if len(violations) > 0 {
+			fmt.Fprintln(os.Stderr)
+			fmt.Fprintf(os.Stderr, "Imports are banned, not in the grouping [%s] or not sorted:\n", strings.Join(classifier.Names(), " "))
+		}
+		for _, v := range violations {
+			fmt.Fprintln(os.Stderr)
+			fmt.Fprintln(os.Stderr, v.String())
+			fmt.Fprint(os.Stderr, v.Diff)
 		}
 	}
 
 	os.Exit(exitCode)
 }
 
-func verifyPackage(stderr io.Writer, pkg *packages.Package) (misgrouped, unsorted []Imports) {
+func verifyPackage(stderr io.Writer, pkg *packages.Package, classifier *importcheck.Classifier, policy importcheck.Policy, fix bool) (violations []importcheck.Violation) {
 	// ignore generated test binaries
 	if strings.HasSuffix(pkg.ID, ".test") {
-		return nil, nil
+		return nil
 	}
 
 	fset := token.NewFileSet()
 	var files []*ast.File
+	var sources [][]byte
 	for _, path := range pkg.GoFiles {
-		file, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
+		src, err := os.ReadFile(path)
+		if err != nil {
+			panic(err)
+		}
+		file, err := parser.ParseFile(fset, path, src, parser.ImportsOnly|parser.ParseComments)
 		if err != nil {
 			panic(err)
 		}
 		files = append(files, file)
+		sources = append(sources, src)
 	}
 
 	for i, path := range pkg.GoFiles {
 		file := files[i]
-		imports := LoadImports(fset, path, file)
+		imports := importcheck.LoadImports(fset, path, file)
+		generated := isGenerated(sources[i])
+
+		if banned := policy.Check(fset, file, path, pkg.PkgPath); len(banned) > 0 {
+			if generated {
+				_, _ = fmt.Fprintln(stderr, "(ignoring generated)", path)
+				continue
+			}
+			violations = append(violations, banned...)
+		}
 
 		ordered := true
 		sorted := true
 		for _, section := range imports.Decls {
-			if !section.IsGrouped() {
+			if !section.IsGrouped(classifier) {
 				ordered = false
 			}
 			if !section.IsSorted() {
@@ -148,221 +235,62 @@ func verifyPackage(stderr io.Writer, pkg *packages.Package) (misgrouped, unsorte
 		}
 
 		if !ordered || !sorted {
-			if isGenerated(path) {
+			if generated {
 				_, _ = fmt.Fprintln(stderr, "(ignoring generated)", path)
 				continue
 			}
-		}
-
-		if !ordered {
-			misgrouped = append(misgrouped, imports)
-		}
-		if !sorted {
-			unsorted = append(unsorted, imports)
-		}
-	}
-
-	return misgrouped, unsorted
-}
 
-// Imports defines all imports for a single file.
-type Imports struct {
-	Path      string
-	Generated bool
-	Decls     []ImportDecl
-}
-
-// Classes returns all import groupings.
-func (imports Imports) Classes() [][]Class {
-	var classes [][]Class
-	for _, decl := range imports.Decls {
-		classes = append(classes, decl.Classes())
-	}
-	return classes
-}
-
-// ImportDecl defines a single import declaration.
-type ImportDecl []ImportGroup
-
-// allowedGroups lists all valid groupings.
-var allowedGroups = [][]Class{
-	{Standard},
-	{Storj},
-	{Other},
-	{Standard, Storj},
-	{Standard, Other},
-	{Other, Storj},
-	{Standard, Other, Storj},
-}
-
-// IsGrouped returns whether the grouping is allowed.
-func (decls ImportDecl) IsGrouped() bool {
-	classes := decls.Classes()
-	for _, allowedGroup := range allowedGroups {
-		if reflect.DeepEqual(allowedGroup, classes) {
-			return true
+			if fix {
+				if err := fixFile(classifier, fset, file, path, sources[i]); err != nil {
+					_, _ = fmt.Fprintf(stderr, "failed to fix %v: %v\n", path, err)
+				} else {
+					continue
+				}
+			}
 		}
-	}
-	return false
-}
 
-// Classes returns each group class.
-func (decls ImportDecl) Classes() []Class {
-	classes := make([]Class, len(decls))
-	for i := range classes {
-		classes[i] = decls[i].Class()
+		violations = append(violations, importcheck.FileViolations(classifier, fset, file, sources[i], imports)...)
 	}
-	return classes
-}
 
-// IsSorted returns whether the group is sorted.
-func (decls ImportDecl) IsSorted() bool {
-	for _, decl := range decls {
-		if !decl.IsSorted() {
-			return false
-		}
-	}
-	return true
+	return violations
 }
 
-// ImportGroup defines a single import statement.
-type ImportGroup struct {
-	Specs []*ast.ImportSpec
-	Paths []string
-}
-
-// IsSorted returns whether the group is sorted.
-func (group ImportGroup) IsSorted() bool {
-	return sort.StringsAreSorted(group.Paths)
-}
-
-// Class returns the classification of this import group.
-func (group ImportGroup) Class() Class {
-	var class Class
-	for _, path := range group.Paths {
-		class |= ClassifyImport(path)
-	}
-	return class
-}
-
-// Class defines a bitset of import classification.
-type Class byte
-
-// Class defines three different groups.
-const (
-	// Standard is all go standard packages.
-	Standard Class = 1 << iota
-	// Storj is imports that start with `storj.io`.
-	Storj
-	// Other is everything else.
-	Other
-)
-
-// ClassifyImport classifies an import path to a class.
-func ClassifyImport(pkgPath string) Class {
-	if strings.HasPrefix(pkgPath, "storj.io/") {
-		return Storj
-	}
-	// https://github.com/golang/go/blob/master/src/cmd/go/internal/search/search.go#L554
-	{
-		i := strings.Index(pkgPath, "/")
-		if i < 0 {
-			i = len(pkgPath)
-		}
-		if !strings.Contains(pkgPath[:i], ".") {
-			return Standard
-		}
+// fixFile rewrites the import blocks of the file at path.
+func fixFile(classifier *importcheck.Classifier, fset *token.FileSet, file *ast.File, path string, src []byte) error {
+	fixed, err := importcheck.FixImports(classifier, fset, file, src)
+	if err != nil {
+		return err
 	}
-	return Other
-}
 
-// String returns contents of the class.
-func (class Class) String() string {
-	var s []string
-	if class&Standard != 0 {
-		s = append(s, "std")
-	}
-	if class&Storj != 0 {
-		s = append(s, "storj")
-	}
-	if class&Other != 0 {
-		s = append(s, "other")
+	stat, err := os.Stat(path)
+	if err != nil {
+		return err
 	}
-	return strings.Join(s, "|")
+	return os.WriteFile(path, fixed, stat.Mode())
 }
 
-// LoadImports loads import groups from a given fileset.
-func LoadImports(fset *token.FileSet, name string, f *ast.File) Imports {
-	var imports Imports
-	imports.Path = name
-
-	for _, d := range f.Decls {
-		d, ok := d.(*ast.GenDecl)
-		if !ok || d.Tok != token.IMPORT {
-			// Not an import declaration, so we're done.
-			// Imports are always first.
-			break
-		}
-
-		if !d.Lparen.IsValid() {
-			// Not a block: sorted by default.
-			continue
-		}
-
-		// identify specs on successive lines
-		lastGroup := 0
-		specgroups := [][]ast.Spec{}
-		for i, s := range d.Specs {
-			if i > lastGroup && fset.Position(s.Pos()).Line > 1+fset.Position(d.Specs[i-1].End()).Line {
-				// i begins a new run. End this one.
-				specgroups = append(specgroups, d.Specs[lastGroup:i])
-				lastGroup = i
-			}
-		}
-		specgroups = append(specgroups, d.Specs[lastGroup:])
-
-		// convert ast.Spec-s groups into import groups
-		var decl ImportDecl
-		for _, specgroup := range specgroups {
-			var group ImportGroup
-			for _, importSpec := range specgroup {
-				importSpec := importSpec.(*ast.ImportSpec)
-				path, err := strconv.Unquote(importSpec.Path.Value)
-				if err != nil {
-					panic(err)
-				}
-				group.Specs = append(group.Specs, importSpec)
-				group.Paths = append(group.Paths, path)
-			}
-			decl = append(decl, group)
-		}
-
-		imports.Decls = append(imports.Decls, decl)
+// isVetTool returns whether the tool is run by "go vet -vettool", which
+// either queries the tool with a single -V=full or -flags argument, or runs
+// it with the analyzer flags followed by a single vet configuration file.
+func isVetTool(args []string) bool {
+	if len(args) == 1 && (args[0] == "-V=full" || args[0] == "-flags") {
+		return true
 	}
-
-	return imports
-}
-
-func isGenerated(path string) bool {
-	file, err := os.Open(path)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to read %v: %v\n", path, err)
+	if len(args) == 0 || !strings.HasSuffix(args[len(args)-1], ".cfg") {
 		return false
 	}
-	defer func() {
-		if err := file.Close(); err != nil {
-			fmt.Fprintln(os.Stderr, err)
+	for _, arg := range args[:len(args)-1] {
+		if !strings.HasPrefix(arg, "-") {
+			return false
 		}
-	}()
-
-	var header [256]byte
-	n, err := file.Read(header[:])
-	if err != nil && !errors.Is(err, io.EOF) {
-		fmt.Fprintf(os.Stderr, "failed to read %v: %v\n", path, err)
-		return false
 	}
+	return true
+}
 
-	return bytes.Contains(header[:n], []byte(`AUTOGENERATED`)) ||
-		bytes.Contains(header[:n], []byte(`Code generated`)) ||
-		bytes.Contains(header[:n], []byte(`Autogenerated`))
+// isGenerated returns whether the header of the source marks it as generated.
+func isGenerated(src []byte) bool {
+	header := src[:min(len(src), 256)]
+	return bytes.Contains(header, []byte(`AUTOGENERATED`)) ||
+		bytes.Contains(header, []byte(`Code generated`)) ||
+		bytes.Contains(header, []byte(`Autogenerated`))
 }
@@ -0,0 +1,28 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import "testing"
+
+func TestIsVetTool(t *testing.T) {
+	for _, test := range []struct {
+		args []string
+		vet  bool
+	}{
+		{[]string{"-V=full"}, true},
+		{[]string{"-flags"}, true},
+		{[]string{"/tmp/go-build/b001/vet.cfg"}, true},
+		{[]string{"-checkimports.policy=policy.json", "/tmp/go-build/b001/vet.cfg"}, true},
+		{[]string{}, false},
+		{[]string{"./..."}, false},
+		{[]string{"-fix", "./..."}, false},
+		{[]string{"-V=full", "./..."}, false},
+		{[]string{"./testdata/a.cfg", "./..."}, false},
+		{[]string{"./...", "vet.cfg"}, false},
+	} {
+		if got := isVetTool(test.args); got != test.vet {
+			t.Errorf("isVetTool(%q) = %v, want %v", test.args, got, test.vet)
+		}
+	}
+}
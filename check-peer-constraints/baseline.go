@@ -0,0 +1,157 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Edge is an import of a package in a forbidden layer, by a package that the
+// source layer depends on. Baselining the imports, rather than every pair of
+// dependent packages, keeps a single offending import a single entry.
+type Edge struct {
+	From string
+	To   string
+}
+
+// String returns the edge as written in a baseline file.
+func (edge Edge) String() string { return edge.From + " -> " + edge.To }
+
+// LoadBaseline reads the accepted edges from a baseline file, which lists an
+// edge per line as "from -> to". Empty lines and lines starting with # are
+// ignored.
+func LoadBaseline(path string) (map[Edge]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline := map[Edge]bool{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		from, to, ok := strings.Cut(text, " -> ")
+		if !ok {
+			return nil, fmt.Errorf("invalid baseline %v:%d: expected \"from -> to\"", path, line)
+		}
+		baseline[Edge{From: strings.TrimSpace(from), To: strings.TrimSpace(to)}] = true
+	}
+	return baseline, scanner.Err()
+}
+
+// WriteBaseline writes the edges sorted to a baseline file.
+func WriteBaseline(path string, edges []Edge) error {
+	lines := make([]string, 0, len(edges))
+	for _, edge := range edges {
+		lines = append(lines, edge.String())
+	}
+	slices.Sort(lines)
+	lines = slices.Compact(lines)
+
+	var buf bytes.Buffer
+	buf.WriteString("# accepted dependencies, regenerate with check-peer-constraints -write-baseline\n")
+	for _, line := range lines {
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// violatingEdges returns the imports through which the source packages depend
+// on the destination packages. Only the import that first enters the
+// destination along a chain is an edge, so that every offending import is
+// reported once, however many source packages depend on it.
+func violatingEdges(source, destination []*packages.Package) []Edge {
+	targets := map[string]bool{}
+	for _, dst := range destination {
+		if ignorePkg(dst) {
+			continue
+		}
+		targets[dst.PkgPath] = true
+	}
+
+	// breadth first search from all sources at once, which doesn't
+	// continue into the destination.
+	var edges []Edge
+	found := map[Edge]bool{}
+	visited := map[*packages.Package]bool{}
+	queue := append([]*packages.Package{}, source...)
+	for _, pkg := range source {
+		visited[pkg] = true
+	}
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
+		for _, path := range sortedImports(pkg) {
+			imp := pkg.Imports[path]
+			if targets[imp.PkgPath] {
+				edge := Edge{From: pkg.PkgPath, To: imp.PkgPath}
+				if !found[edge] {
+					found[edge] = true
+					edges = append(edges, edge)
+				}
+				continue
+			}
+			if !visited[imp] {
+				visited[imp] = true
+				queue = append(queue, imp)
+			}
+		}
+	}
+
+	sort.Slice(edges, func(i, k int) bool {
+		if edges[i].From != edges[k].From {
+			return edges[i].From < edges[k].From
+		}
+		return edges[i].To < edges[k].To
+	})
+	return edges
+}
+
+// checkBaseline reports the forbidden edges that are not in the baseline
+// and the baseline edges that no longer exist. It returns the exit code.
+func checkBaseline(pkgs []*packages.Package, rules []Rule, baseline map[Edge]bool) int {
+	exitcode := 0
+	positions := importPositions{}
+	present := map[Edge]bool{}
+	for _, rule := range rules {
+		var fresh []Edge
+		for _, edge := range violatingEdges(match(pkgs, rule.Source), match(pkgs, rule.Target)) {
+			present[edge] = true
+			if !baseline[edge] {
+				fresh = append(fresh, edge)
+			}
+		}
+		if len(fresh) == 0 {
+			continue
+		}
+
+		printViolations(os.Stdout, pkgs, rule, fresh, positions)
+		exitcode = 1
+	}
+
+	var stale []string
+	for edge := range baseline {
+		if !present[edge] {
+			stale = append(stale, edge.String())
+		}
+	}
+	sort.Strings(stale)
+	for _, edge := range stale {
+		_, _ = fmt.Fprintf(os.Stdout, "# no longer present, remove from baseline: %s\n", edge)
+	}
+
+	return exitcode
+}
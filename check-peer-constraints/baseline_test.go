@@ -0,0 +1,40 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBaseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.txt")
+
+	edges := []Edge{
+		{From: "storj.io/storj/private/b", To: "storj.io/storj/satellite"},
+		{From: "storj.io/storj/private/a", To: "storj.io/storj/satellite"},
+		{From: "storj.io/storj/private/b", To: "storj.io/storj/satellite"},
+	}
+	if err := WriteBaseline(path, edges); err != nil {
+		t.Fatal(err)
+	}
+
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[Edge]bool{edges[0]: true, edges[1]: true}
+	if !reflect.DeepEqual(baseline, want) {
+		t.Errorf("got %v, want %v", baseline, want)
+	}
+
+	if err := os.WriteFile(path, []byte("storj.io/storj/private/a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBaseline(path); err == nil {
+		t.Error("expected an error for an invalid line")
+	}
+}
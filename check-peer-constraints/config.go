@@ -0,0 +1,166 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config declares the layers of a repository and the imports between them
+// that are forbidden.
+type Config struct {
+	// Packages are the patterns loaded to build the import graph.
+	Packages []string `yaml:"packages,omitempty"`
+	Layers   []Layer  `yaml:"layers"`
+}
+
+// Layer is a named group of package patterns.
+//
+// Patterns are either exact paths or paths ending with "/...", which match
+// the path and everything below it.
+type Layer struct {
+	Name     string   `yaml:"name"`
+	Packages []string `yaml:"packages"`
+	// MayNotImport lists the layers this layer must not depend on.
+	MayNotImport []string `yaml:"may_not_import,omitempty"`
+	// Isolated forbids the patterns of the layer from depending on each
+	// other, except for their own subpackages.
+	Isolated bool `yaml:"isolated,omitempty"`
+}
+
+// DefaultConfig contains the layers of storj.io/storj.
+var DefaultConfig = Config{
+	Packages: []string{"storj.io/storj/..."},
+	Layers: []Layer{
+		{
+			Name: "libraries",
+			Packages: []string{
+				"storj.io/storj/pkg/...",
+				"storj.io/storj/private/...",
+				"storj.io/storj/storage/...",
+			},
+			MayNotImport: []string{"cmds", "peers"},
+		},
+		{
+			Name: "peers",
+			Packages: []string{
+				"storj.io/storj/satellite/...",
+				"storj.io/storj/storagenode/...",
+				"storj.io/storj/versioncontrol/...",
+				"storj.io/storj/certificate/...",
+				"storj.io/storj/multinode/...",
+			},
+			MayNotImport: []string{"cmds"},
+			Isolated:     true,
+		},
+		{
+			Name:     "cmds",
+			Packages: []string{"storj.io/storj/cmd/..."},
+		},
+	},
+}
+
+// Rule forbids packages matching Source from depending on packages matching
+// Target.
+type Rule struct {
+	Layer  string
+	Source string
+	Target string
+}
+
+// LoadConfig loads and verifies a YAML layer configuration file. Unknown
+// fields are rejected to catch misspelled options.
+func LoadConfig(path string) (Config, error) {
+	var config Config
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return config, err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		return config, fmt.Errorf("invalid config %v: %w", path, err)
+	}
+	if err := config.Verify(); err != nil {
+		return config, fmt.Errorf("invalid config %v: %w", path, err)
+	}
+	return config, nil
+}
+
+// Verify checks that the layers are named uniquely and refer to existing
+// layers.
+func (config Config) Verify() error {
+	names := map[string]bool{}
+	for i, layer := range config.Layers {
+		if layer.Name == "" {
+			return fmt.Errorf("layer %d has no name", i+1)
+		}
+		if names[layer.Name] {
+			return fmt.Errorf("duplicate layer %q", layer.Name)
+		}
+		if len(layer.Packages) == 0 {
+			return fmt.Errorf("layer %q has no packages", layer.Name)
+		}
+		names[layer.Name] = true
+	}
+
+	for _, layer := range config.Layers {
+		for _, name := range layer.MayNotImport {
+			if !names[name] {
+				return fmt.Errorf("layer %q refers to unknown layer %q", layer.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// Rules expands the layers into rules between package patterns.
+func (config Config) Rules() []Rule {
+	layers := map[string]Layer{}
+	for _, layer := range config.Layers {
+		layers[layer.Name] = layer
+	}
+
+	var rules []Rule
+	for _, layer := range config.Layers {
+		for _, source := range layer.Packages {
+			for _, name := range layer.MayNotImport {
+				for _, target := range layers[name].Packages {
+					rules = append(rules, Rule{Layer: layer.Name, Source: source, Target: target})
+				}
+			}
+
+			if !layer.Isolated {
+				continue
+			}
+			for _, target := range layer.Packages {
+				// ignore subpackages
+				if nested(source, target) || nested(target, source) {
+					continue
+				}
+				rules = append(rules, Rule{Layer: layer.Name, Source: source, Target: target})
+			}
+		}
+	}
+	return rules
+}
+
+// nested returns whether the pattern inner matches only packages that are
+// also matched by outer.
+func nested(inner, outer string) bool {
+	base, ok := strings.CutSuffix(outer, "/...")
+	if !ok {
+		return inner == outer
+	}
+	inner = strings.TrimSuffix(inner, "/...")
+	return inner == base || strings.HasPrefix(inner, base+"/")
+}
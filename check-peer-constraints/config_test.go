@@ -0,0 +1,84 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDefaultConfigRules(t *testing.T) {
+	if err := DefaultConfig.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := DefaultConfig.Rules()
+	// libraries: 3 sources * (1 cmd + 5 peers), peers: 5 * 1 cmd + 5 * 4 other peers
+	if len(rules) != 18+5+20 {
+		t.Fatalf("got %d rules", len(rules))
+	}
+	want := Rule{Layer: "libraries", Source: "storj.io/storj/pkg/...", Target: "storj.io/storj/cmd/..."}
+	if rules[0] != want {
+		t.Errorf("got %+v, want %+v", rules[0], want)
+	}
+}
+
+func TestConfigRules(t *testing.T) {
+	config := Config{Layers: []Layer{
+		{Name: "lib", Packages: []string{"a/lib/..."}, MayNotImport: []string{"app"}},
+		{Name: "app", Packages: []string{"a/app/...", "a/app/x/...", "a/web"}, Isolated: true},
+	}}
+	if err := config.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Rule{
+		{Layer: "lib", Source: "a/lib/...", Target: "a/app/..."},
+		{Layer: "lib", Source: "a/lib/...", Target: "a/app/x/..."},
+		{Layer: "lib", Source: "a/lib/...", Target: "a/web"},
+		{Layer: "app", Source: "a/app/...", Target: "a/web"},
+		{Layer: "app", Source: "a/app/x/...", Target: "a/web"},
+		{Layer: "app", Source: "a/web", Target: "a/app/..."},
+		{Layer: "app", Source: "a/web", Target: "a/app/x/..."},
+	}
+	if got := config.Rules(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestConfigVerify(t *testing.T) {
+	for _, config := range []Config{
+		{Layers: []Layer{{Packages: []string{"a/..."}}}},
+		{Layers: []Layer{{Name: "a"}}},
+		{Layers: []Layer{{Name: "a", Packages: []string{"a/..."}}, {Name: "a", Packages: []string{"b/..."}}}},
+		{Layers: []Layer{{Name: "a", Packages: []string{"a/..."}, MayNotImport: []string{"b"}}}},
+	} {
+		if err := config.Verify(); err == nil {
+			t.Errorf("expected an error for %+v", config)
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	config, err := LoadConfig("testdata/config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Config{
+		Packages: []string{"./..."},
+		Layers: []Layer{
+			{Name: "libraries", Packages: []string{"storj.io/edge/pkg/..."}, MayNotImport: []string{"cmds", "services"}},
+			{Name: "services", Packages: []string{"storj.io/edge/pkg/server/...", "storj.io/edge/pkg/auth/..."}, MayNotImport: []string{"cmds"}, Isolated: true},
+			{Name: "cmds", Packages: []string{"storj.io/edge/cmd/..."}},
+		},
+	}
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("got %+v, want %+v", config, want)
+	}
+
+	if _, err := LoadConfig("testdata/unknown.yaml"); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// graph is a dependency graph between layers or packages.
+type graph struct {
+	name     string
+	nodes    []string
+	clusters []cluster
+	edges    []graphEdge
+}
+
+// cluster groups the nodes belonging to a layer.
+type cluster struct {
+	name  string
+	nodes []string
+}
+
+// graphEdge is a dependency between two nodes.
+type graphEdge struct {
+	from, to  string
+	forbidden bool
+}
+
+// buildGraph creates the dependency graph between the layers of config, or
+// between their packages when packageLevel is set. Packages outside of any
+// layer are looked through, so that their dependencies become edges between
+// the layers they connect.
+func buildGraph(pkgs []*packages.Package, config Config, packageLevel bool) *graph {
+	layers := make([]*regexp.Regexp, len(config.Layers))
+	for i, layer := range config.Layers {
+		layers[i] = globRegexp(layer.Packages...)
+	}
+
+	g := &graph{name: "layers"}
+	if packageLevel {
+		g.name = "packages"
+		g.clusters = make([]cluster, len(config.Layers))
+		for i, layer := range config.Layers {
+			g.clusters[i].name = layer.Name
+		}
+	} else {
+		for _, layer := range config.Layers {
+			g.nodes = append(g.nodes, layer.Name)
+		}
+	}
+
+	layerOf := map[*packages.Package]int{}
+	for _, pkg := range pkgs {
+		if ignorePkg(pkg) {
+			continue
+		}
+		for i, rx := range layers {
+			if rx.MatchString(pkg.PkgPath) {
+				layerOf[pkg] = i
+				if packageLevel {
+					g.clusters[i].nodes = append(g.clusters[i].nodes, pkg.PkgPath)
+				}
+				break
+			}
+		}
+	}
+
+	type compiledRule struct{ source, target *regexp.Regexp }
+	var rules []compiledRule
+	for _, rule := range config.Rules() {
+		rules = append(rules, compiledRule{globRegexp(rule.Source), globRegexp(rule.Target)})
+	}
+	forbidden := func(from, to string) bool {
+		for _, rule := range rules {
+			if rule.source.MatchString(from) && rule.target.MatchString(to) {
+				return true
+			}
+		}
+		return false
+	}
+
+	edges := map[[2]string]int{}
+	addEdge := func(from, to string, bad bool) {
+		key := [2]string{from, to}
+		if i, ok := edges[key]; ok {
+			g.edges[i].forbidden = g.edges[i].forbidden || bad
+			return
+		}
+		edges[key] = len(g.edges)
+		g.edges = append(g.edges, graphEdge{from: from, to: to, forbidden: bad})
+	}
+
+	for _, pkg := range pkgs {
+		from, ok := layerOf[pkg]
+		if !ok {
+			continue
+		}
+
+		visited := map[*packages.Package]bool{}
+		var visit func(at *packages.Package)
+		visit = func(at *packages.Package) {
+			for _, path := range sortedImports(at) {
+				imp := at.Imports[path]
+				if visited[imp] || ignorePkg(imp) {
+					continue
+				}
+				visited[imp] = true
+
+				to, ok := layerOf[imp]
+				if !ok {
+					visit(imp)
+					continue
+				}
+
+				bad := forbidden(pkg.PkgPath, imp.PkgPath)
+				switch {
+				case packageLevel:
+					addEdge(pkg.PkgPath, imp.PkgPath, bad)
+				case from != to || bad:
+					addEdge(config.Layers[from].Name, config.Layers[to].Name, bad)
+				}
+			}
+		}
+		visit(pkg)
+	}
+
+	return g
+}
+
+// writeDOT writes the graph in Graphviz DOT format.
+func (g *graph) writeDOT(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "digraph %s {\n", g.name)
+	for _, node := range g.nodes {
+		_, _ = fmt.Fprintf(w, "\t%q;\n", node)
+	}
+	for _, c := range g.clusters {
+		if len(c.nodes) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "\tsubgraph %q {\n", "cluster_"+c.name)
+		_, _ = fmt.Fprintf(w, "\t\tlabel = %q;\n", c.name)
+		for _, node := range c.nodes {
+			_, _ = fmt.Fprintf(w, "\t\t%q;\n", node)
+		}
+		_, _ = fmt.Fprintf(w, "\t}\n")
+	}
+	for _, edge := range g.edges {
+		if edge.forbidden {
+			_, _ = fmt.Fprintf(w, "\t%q -> %q [color=red];\n", edge.from, edge.to)
+		} else {
+			_, _ = fmt.Fprintf(w, "\t%q -> %q;\n", edge.from, edge.to)
+		}
+	}
+	_, _ = fmt.Fprintf(w, "}\n")
+}
+
+// writeMermaid writes the graph as a Mermaid flowchart.
+func (g *graph) writeMermaid(w io.Writer) {
+	ids := map[string]string{}
+	node := func(indent, name string) {
+		ids[name] = fmt.Sprintf("n%d", len(ids))
+		_, _ = fmt.Fprintf(w, "%s%s[%q]\n", indent, ids[name], name)
+	}
+
+	_, _ = fmt.Fprintf(w, "graph LR\n")
+	for _, name := range g.nodes {
+		node("\t", name)
+	}
+	for i, c := range g.clusters {
+		if len(c.nodes) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "\tsubgraph c%d[%q]\n", i, c.name)
+		for _, name := range c.nodes {
+			node("\t\t", name)
+		}
+		_, _ = fmt.Fprintf(w, "\tend\n")
+	}
+	for _, edge := range g.edges {
+		_, _ = fmt.Fprintf(w, "\t%s --> %s\n", ids[edge.from], ids[edge.to])
+	}
+	for i, edge := range g.edges {
+		if edge.forbidden {
+			_, _ = fmt.Fprintf(w, "\tlinkStyle %d stroke:red\n", i)
+		}
+	}
+}
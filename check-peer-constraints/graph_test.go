@@ -0,0 +1,93 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func TestGraphOutput(t *testing.T) {
+	g := &graph{
+		name:  "layers",
+		nodes: []string{"libs", "peers"},
+		edges: []graphEdge{
+			{from: "peers", to: "libs"},
+			{from: "libs", to: "peers", forbidden: true},
+		},
+	}
+
+	var dot strings.Builder
+	g.writeDOT(&dot)
+	wantDOT := `digraph layers {
+	"libs";
+	"peers";
+	"peers" -> "libs";
+	"libs" -> "peers" [color=red];
+}
+`
+	if dot.String() != wantDOT {
+		t.Errorf("got %s, want %s", dot.String(), wantDOT)
+	}
+
+	var mermaid strings.Builder
+	g.writeMermaid(&mermaid)
+	wantMermaid := `graph LR
+	n0["libs"]
+	n1["peers"]
+	n1 --> n0
+	n0 --> n1
+	linkStyle 1 stroke:red
+`
+	if mermaid.String() != wantMermaid {
+		t.Errorf("got %s, want %s", mermaid.String(), wantMermaid)
+	}
+}
+
+func TestBuildGraph(t *testing.T) {
+	pkgs := testPackages()
+	// a dependency within a layer, which is only shown between packages
+	pkgs["a/lib/sub"].Imports["a/lib"] = pkgs["a/lib"]
+	all := flatten([]*packages.Package{pkgs["a/lib/sub"]})
+
+	config := Config{Layers: []Layer{
+		{Name: "lib", Packages: []string{"a/lib/..."}, MayNotImport: []string{"app"}},
+		{Name: "app", Packages: []string{"a/app"}},
+		{Name: "web", Packages: []string{"a/web"}},
+	}}
+
+	// a/util is outside of the layers, so a/lib depends on a/app through it
+	want := &graph{
+		name:  "layers",
+		nodes: []string{"lib", "app", "web"},
+		edges: []graphEdge{
+			{from: "lib", to: "app", forbidden: true},
+			{from: "lib", to: "web"},
+		},
+	}
+	if got := buildGraph(all, config, false); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	want = &graph{
+		name: "packages",
+		clusters: []cluster{
+			{name: "lib", nodes: []string{"a/lib", "a/lib/sub"}},
+			{name: "app", nodes: []string{"a/app"}},
+			{name: "web", nodes: []string{"a/web"}},
+		},
+		edges: []graphEdge{
+			{from: "a/lib", to: "a/app", forbidden: true},
+			{from: "a/lib/sub", to: "a/app", forbidden: true},
+			{from: "a/lib/sub", to: "a/lib"},
+			{from: "a/lib/sub", to: "a/web"},
+		},
+	}
+	if got := buildGraph(all, config, true); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
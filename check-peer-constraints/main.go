@@ -1,45 +1,104 @@
 // Copyright (C) 2019 Storj Labs, Inc.
 // See LICENSE for copying information.
 
-// check-peer-constraints checks that none of the core packages import peers directly.
+/*
+check-peer-constraints checks that packages do not import the layers they
+must not depend on.
+
+By default it verifies the layers of storj.io/storj: libraries must not
+import peers nor commands, peers must not import commands nor each other.
+Other repositories can declare their own layers with -config, which takes a
+YAML file, e.g.:
+
+	packages: ["./..."]
+	layers:
+	  - name: libraries
+	    packages: ["storj.io/edge/pkg/..."]
+	    may_not_import: [cmds, services]
+	  - name: services
+	    packages: ["storj.io/edge/pkg/server/...", "storj.io/edge/pkg/auth/..."]
+	    may_not_import: [cmds]
+	    isolated: true
+	  - name: cmds
+	    packages: ["storj.io/edge/cmd/..."]
+
+Patterns are either exact paths or paths ending with "/...", which match the
+path and everything below it. An isolated layer forbids its patterns from
+importing each other, except for their own subpackages. The packages loaded
+to build the import graph default to "./..." and can be overridden by
+passing patterns as arguments.
+
+With -graph the dependencies between the layers are printed as a Graphviz
+DOT or Mermaid graph instead, with forbidden dependencies in red. Packages
+outside of any layer are collapsed into the edges between the layers they
+connect. With -graph-packages the graph shows the individual packages
+grouped by their layer.
+
+To introduce rules to a repository with existing violations, -write-baseline
+writes every current forbidden import, as "from -> to" package pairs, to the
+-baseline file. Only the imports entering the forbidden layer are written, not
+every package depending on them. Checking with -baseline then fails only for dependencies
+missing from it and lists the baseline entries that no longer exist, so the
+baseline can shrink over time.
+*/
 package main
 
 import (
 	"flag"
 	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
 	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
 )
 
-// Libraries contains the list of packages to verify against.
-var Libraries = []string{
-	"storj.io/storj/pkg/...",
-	"storj.io/storj/private/...",
-	"storj.io/storj/storage/...",
-}
+var (
+	race          = flag.Bool("race", false, "load with race tag")
+	configPath    = flag.String("config", "", "YAML file declaring the layers and their forbidden imports")
+	graphFormat   = flag.String("graph", "", "print the dependency graph as \"dot\" or \"mermaid\" instead of checking")
+	graphPackages = flag.Bool("graph-packages", false, "graph the dependencies between packages instead of layers")
+	baselinePath  = flag.String("baseline", "", "file listing accepted forbidden dependencies, only new ones fail")
+	writeBaseline = flag.Bool("write-baseline", false, "write all current forbidden imports to the -baseline file")
+)
 
-// Peers contains the list of peer pacakges.
-var Peers = []string{
-	"storj.io/storj/satellite/...",
-	"storj.io/storj/storagenode/...",
-	"storj.io/storj/versioncontrol/...",
-	"storj.io/storj/certificate/...",
-	"storj.io/storj/multinode/...",
-}
+func main() {
+	flag.Parse()
 
-// Cmds contains the list of command packages.
-var Cmds = []string{
-	"storj.io/storj/cmd/...",
-}
+	config := DefaultConfig
+	if *configPath != "" {
+		var err error
+		config, err = LoadConfig(*configPath)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
-var race = flag.Bool("race", false, "load with race tag")
+	if *graphFormat != "" && *graphFormat != "dot" && *graphFormat != "mermaid" {
+		_, _ = fmt.Fprintf(os.Stderr, "unknown graph format %q\n", *graphFormat)
+		os.Exit(1)
+	}
 
-func main() {
-	flag.Parse()
+	if *writeBaseline && *baselinePath == "" {
+		_, _ = fmt.Fprintf(os.Stderr, "-write-baseline requires -baseline\n")
+		os.Exit(1)
+	}
+
+	patterns := config.Packages
+	if flag.NArg() > 0 {
+		patterns = flag.Args()
+	}
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
 
 	var buildFlags []string
 	if *race {
@@ -51,72 +110,60 @@ func main() {
 		Env:        os.Environ(),
 		BuildFlags: buildFlags,
 		Tests:      false,
-	}, "storj.io/storj/...")
+	}, patterns...)
 	if err != nil {
 		_, _ = fmt.Fprintf(os.Stderr, "failed to load pacakges: %v\n", err)
 		os.Exit(1)
 	}
 	pkgs = flatten(pkgs)
 
-	exitcode := 0
+	if *graphFormat != "" {
+		g := buildGraph(pkgs, config, *graphPackages)
+		if *graphFormat == "mermaid" {
+			g.writeMermaid(os.Stdout)
+		} else {
+			g.writeDOT(os.Stdout)
+		}
+		return
+	}
 
-	// libraries shouldn't depend on peers nor commands
-	for _, library := range Libraries {
-		source := match(pkgs, library)
-		for _, peer := range include(Cmds, Peers) {
-			destination := match(pkgs, peer)
-			if links(source, destination) {
-				_, _ = fmt.Fprintf(os.Stdout, "%q is importing %q\n", library, peer)
-				exitcode = 1
-			}
+	if *writeBaseline {
+		var edges []Edge
+		for _, rule := range config.Rules() {
+			edges = append(edges, violatingEdges(match(pkgs, rule.Source), match(pkgs, rule.Target))...)
+		}
+		if err := WriteBaseline(*baselinePath, edges); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to write baseline: %v\n", err)
+			os.Exit(1)
 		}
+		return
 	}
 
-	// peer code shouldn't depend on command code
-	for _, peer := range Peers {
-		source := match(pkgs, peer)
-		for _, cmd := range Cmds {
-			destination := match(pkgs, cmd)
-			if links(source, destination) {
-				_, _ = fmt.Fprintf(os.Stdout, "%q is importing %q\n", peer, cmd)
-				exitcode = 1
-			}
+	if *baselinePath != "" {
+		baseline, err := LoadBaseline(*baselinePath)
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "failed to load baseline: %v\n", err)
+			os.Exit(1)
 		}
+		os.Exit(checkBaseline(pkgs, config.Rules(), baseline))
 	}
 
-	// one peer shouldn't depend on another peers
-	for _, peerA := range Peers {
-		source := match(pkgs, peerA)
-		for _, peerB := range Peers {
-			// ignore subpackages
-			if strings.HasPrefix(peerA, peerB) || strings.HasPrefix(peerB, peerA) {
-				continue
-			}
+	exitcode := 0
 
-			destination := match(pkgs, peerB)
-			if links(source, destination) {
-				_, _ = fmt.Fprintf(os.Stdout, "%q is importing %q\n", peerA, peerB)
-				exitcode = 1
-			}
+	positions := importPositions{}
+	for _, rule := range config.Rules() {
+		edges := violatingEdges(match(pkgs, rule.Source), match(pkgs, rule.Target))
+		if len(edges) > 0 {
+			printViolations(os.Stdout, pkgs, rule, edges, positions)
+			exitcode = 1
 		}
 	}
 
 	os.Exit(exitcode)
 }
 
-func include(globlists ...[]string) []string {
-	var xs []string
-	for _, globs := range globlists {
-		xs = append(xs, globs...)
-	}
-	return xs
-}
-
 func match(pkgs []*packages.Package, globs ...string) []*packages.Package {
-	for i, glob := range globs {
-		globs[i] = strings.ReplaceAll(glob, "...", ".*")
-	}
-	rx := regexp.MustCompile("^(" + strings.Join(globs, "|") + ")$")
+	rx := globRegexp(globs...)
 
 	var rs []*packages.Package
 	for _, pkg := range pkgs {
@@ -131,7 +178,21 @@ func match(pkgs []*packages.Package, globs ...string) []*packages.Package {
 	return rs
 }
 
-func links(source, destination []*packages.Package) bool {
+// globRegexp compiles package patterns into a regular expression matching
+// any of them.
+func globRegexp(globs ...string) *regexp.Regexp {
+	rxs := make([]string, len(globs))
+	for i, glob := range globs {
+		glob = regexp.QuoteMeta(glob)
+		glob = strings.ReplaceAll(glob, `/\.\.\.`, "(/.*)?")
+		rxs[i] = strings.ReplaceAll(glob, `\.\.\.`, ".*")
+	}
+	return regexp.MustCompile("^(" + strings.Join(rxs, "|") + ")$")
+}
+
+// shortestChain returns the shortest import chain from any of the source
+// packages to any of the destination packages, or nil when there is none.
+func shortestChain(source, destination []*packages.Package) []*packages.Package {
 	targets := map[string]bool{}
 	for _, dst := range destination {
 		if ignorePkg(dst) {
@@ -140,29 +201,135 @@ func links(source, destination []*packages.Package) bool {
 		targets[dst.PkgPath] = true
 	}
 
-	links := false
-	visited := map[string]bool{}
+	// breadth first search from all sources at once, remembering
+	// where each package was first imported from.
+	importedBy := map[*packages.Package]*packages.Package{}
+	visited := map[*packages.Package]bool{}
+	queue := append([]*packages.Package{}, source...)
+	for _, pkg := range source {
+		visited[pkg] = true
+	}
+
+	for len(queue) > 0 {
+		pkg := queue[0]
+		queue = queue[1:]
 
-	var visit func(pkg *packages.Package, path []*packages.Package)
-	visit = func(pkg *packages.Package, path []*packages.Package) {
-		for id, imp := range pkg.Imports {
-			if _, visited := visited[id]; visited {
+		for _, path := range sortedImports(pkg) {
+			imp := pkg.Imports[path]
+			if visited[imp] {
 				continue
 			}
-			visited[id] = true
-			if targets[id] {
-				links = true
-				fmt.Printf("# import chain %q\n", pathstr(append(path, pkg, imp)))
+			visited[imp] = true
+			importedBy[imp] = pkg
+
+			if targets[imp.PkgPath] {
+				chain := []*packages.Package{imp}
+				for at := pkg; at != nil; at = importedBy[at] {
+					chain = append(chain, at)
+				}
+				slices.Reverse(chain)
+				return chain
 			}
-			visit(imp, append(path, pkg))
+			queue = append(queue, imp)
 		}
 	}
 
-	for _, pkg := range source {
-		visit(pkg, nil)
+	return nil
+}
+
+// printViolations prints the shortest import chain from the sources of the
+// rule through every edge violating it.
+func printViolations(w io.Writer, pkgs []*packages.Package, rule Rule, edges []Edge, positions importPositions) {
+	byPath := map[string]*packages.Package{}
+	for _, pkg := range pkgs {
+		byPath[pkg.PkgPath] = pkg
 	}
+	source := match(pkgs, rule.Source)
+
+	_, _ = fmt.Fprintf(w, "%q is importing %q\n", rule.Source, rule.Target)
+	for _, edge := range edges {
+		from, to := byPath[edge.From], byPath[edge.To]
 
-	return links
+		var chain []*packages.Package
+		if !slices.Contains(source, from) {
+			chain = shortestChain(source, []*packages.Package{from})
+		}
+		if chain == nil {
+			chain = []*packages.Package{from}
+		}
+		printChain(w, append(chain, to), positions)
+	}
+}
+
+// printChain prints the import chain with the position of every import
+// statement along the way.
+func printChain(w io.Writer, chain []*packages.Package, positions importPositions) {
+	_, _ = fmt.Fprintf(w, "\t%s\n", chain[0].PkgPath)
+	for i := 1; i < len(chain); i++ {
+		_, _ = fmt.Fprintf(w, "\t%s: imports %s\n", positions.find(chain[i-1], chain[i]), chain[i].PkgPath)
+	}
+}
+
+// importPositions holds the file and line of the import statements of each
+// package, so that the files of a package are parsed only once.
+type importPositions map[*packages.Package]map[string]string
+
+// find returns the file and line where pkg imports imp, or the package path
+// when the import isn't found in its files.
+func (positions importPositions) find(pkg, imp *packages.Package) string {
+	byPath, ok := positions[pkg]
+	if !ok {
+		byPath = map[string]string{}
+		fset := token.NewFileSet()
+		for _, filename := range pkg.GoFiles {
+			file, err := parser.ParseFile(fset, filename, nil, parser.ImportsOnly)
+			if err != nil {
+				continue
+			}
+			for _, spec := range file.Imports {
+				value, err := strconv.Unquote(spec.Path.Value)
+				if _, seen := byPath[value]; err != nil || seen {
+					continue
+				}
+				position := fset.Position(spec.Pos())
+				byPath[value] = fmt.Sprintf("%s:%d", relativePath(position.Filename), position.Line)
+			}
+		}
+		positions[pkg] = byPath
+	}
+
+	for path, dep := range pkg.Imports {
+		if dep == imp {
+			if position, ok := byPath[path]; ok {
+				return position
+			}
+		}
+	}
+	return pkg.PkgPath
+}
+
+// relativePath returns the filename relative to the working directory when
+// it's inside it.
+func relativePath(filename string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return filename
+	}
+	rel, err := filepath.Rel(wd, filename)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filename
+	}
+	return rel
+}
+
+// sortedImports returns the import paths of pkg in sorted order.
+func sortedImports(pkg *packages.Package) []string {
+	paths := make([]string, 0, len(pkg.Imports))
+	for path := range pkg.Imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
 }
 
 func ignorePkg(pkg *packages.Package) bool {
@@ -195,12 +362,3 @@ func flatten(pkgs []*packages.Package) []*packages.Package {
 
 	return all
 }
-
-func pathstr(path []*packages.Package) string {
-	ids := []string{}
-	for _, pkg := range path {
-		ids = append(ids, pkg.PkgPath)
-	}
-
-	return strings.Join(ids, " > ")
-}
@@ -0,0 +1,153 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// testPackages returns packages where a/lib imports a/app through a/util and
+// a/lib/sub imports a/app and a/web directly.
+func testPackages() map[string]*packages.Package {
+	pkgs := map[string]*packages.Package{}
+	for _, path := range []string{"a/lib", "a/lib/sub", "a/util", "a/app", "a/web"} {
+		pkgs[path] = &packages.Package{PkgPath: path, Imports: map[string]*packages.Package{}}
+	}
+	link := func(from string, to ...string) {
+		for _, path := range to {
+			pkgs[from].Imports[path] = pkgs[path]
+		}
+	}
+	link("a/lib", "a/util")
+	link("a/lib/sub", "a/web", "a/app")
+	link("a/util", "a/app")
+	return pkgs
+}
+
+func chainPaths(chain []*packages.Package) []string {
+	var paths []string
+	for _, pkg := range chain {
+		paths = append(paths, pkg.PkgPath)
+	}
+	return paths
+}
+
+func TestShortestChain(t *testing.T) {
+	pkgs := testPackages()
+
+	chain := shortestChain([]*packages.Package{pkgs["a/lib"]}, []*packages.Package{pkgs["a/app"], pkgs["a/web"]})
+	if got, want := chainPaths(chain), []string{"a/lib", "a/util", "a/app"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// the direct import wins over the longer chain
+	chain = shortestChain([]*packages.Package{pkgs["a/lib"], pkgs["a/lib/sub"]}, []*packages.Package{pkgs["a/app"]})
+	if got, want := chainPaths(chain), []string{"a/lib/sub", "a/app"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if chain := shortestChain([]*packages.Package{pkgs["a/app"]}, []*packages.Package{pkgs["a/lib"]}); chain != nil {
+		t.Errorf("expected no chain, got %v", chainPaths(chain))
+	}
+}
+
+func TestViolatingEdges(t *testing.T) {
+	pkgs := testPackages()
+	// another source depending on a/app through a/util
+	pkgs["a/lib/other"] = &packages.Package{PkgPath: "a/lib/other", Imports: map[string]*packages.Package{"a/util": pkgs["a/util"]}}
+	all := flatten([]*packages.Package{pkgs["a/lib"], pkgs["a/lib/sub"], pkgs["a/lib/other"]})
+
+	// every offending import is an edge once, regardless of the sources
+	// depending on it
+	want := []Edge{
+		{From: "a/lib/sub", To: "a/app"},
+		{From: "a/util", To: "a/app"},
+	}
+	if got := violatingEdges(match(all, "a/lib/..."), match(all, "a/app")); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	want = []Edge{{From: "a/lib/sub", To: "a/web"}}
+	if got := violatingEdges(match(all, "a/lib/..."), match(all, "a/web")); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if got := violatingEdges(match(all, "a/app"), match(all, "a/lib/...")); len(got) != 0 {
+		t.Errorf("expected no edges, got %v", got)
+	}
+}
+
+func TestPrintViolations(t *testing.T) {
+	pkgs := testPackages()
+	all := flatten([]*packages.Package{pkgs["a/lib"], pkgs["a/lib/sub"]})
+
+	rule := Rule{Layer: "lib", Source: "a/lib/...", Target: "a/app"}
+	edges := violatingEdges(match(all, rule.Source), match(all, rule.Target))
+
+	var out strings.Builder
+	printViolations(&out, all, rule, edges, importPositions{})
+
+	// the packages have no files, so the positions fall back to the package
+	want := `"a/lib/..." is importing "a/app"
+	a/lib/sub
+	a/lib/sub: imports a/app
+	a/lib
+	a/lib: imports a/util
+	a/util: imports a/app
+`
+	if out.String() != want {
+		t.Errorf("got %s, want %s", out.String(), want)
+	}
+}
+
+func TestImportPosition(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	files := map[string]string{
+		"a.go": "package lib\n\nimport \"fmt\"\n",
+		"b.go": "package lib\n\nimport (\n\t\"os\"\n\n\tapp \"a/app\"\n)\n",
+	}
+	var filenames []string
+	for name, src := range files {
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		filenames = append(filenames, filename)
+	}
+
+	app := &packages.Package{PkgPath: "a/app"}
+	web := &packages.Package{PkgPath: "a/web"}
+	lib := &packages.Package{
+		PkgPath: "a/lib",
+		GoFiles: filenames,
+		Imports: map[string]*packages.Package{"a/app": app, "a/web": web},
+	}
+
+	positions := importPositions{}
+	if got, want := positions.find(lib, app), "b.go:6"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	// imports missing from the files are reported at the package
+	if got, want := positions.find(lib, web), "a/lib"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// the files are only parsed once
+	for _, filename := range filenames {
+		if err := os.Remove(filename); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := positions.find(lib, app), "b.go:6"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
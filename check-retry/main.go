@@ -2,7 +2,8 @@
 // See LICENSE for copying information.
 
 // check-retry checks that variables modified inside retry callbacks are
-// properly reset at the beginning of the callback body.
+// properly reset at the beginning of the callback body, and that the callbacks
+// do not perform operations that must not be repeated.
 //
 // When a function like WithRetry or ReadWriteTransaction retries the callback,
 // variables from the outer scope retain values from previous attempts. This
@@ -26,12 +27,34 @@
 //	    rows = []int{}
 //	    rows = append(rows, 123)
 //	})
+//
+// The reported variables come with a suggested fix that resets them at the
+// top of the callback, see -fix.
+//
+// Plain assignments at the top of the callback, such as "rows = nil", count
+// as resets unless they refer to the variable itself. Writes through a
+// pointer, such as "*out = Result{}" or "*out = zero", only count as resets
+// when they replace a whole struct, slice, map or other non-scalar value;
+// "*count = 0" is still reported as a modification of the pointee.
+//
+// # Side effects
+//
+// Operations that must not repeat are reported as well: sending to or closing
+// an outer channel, calling a callback passed in as a parameter, or as a field
+// of one, of the enclosing function and calling any of the known
+// side-effecting functions, such as atomic increments, monkit
+// counters or error level logging. Additional functions can be added with
+// -sideeffects:
+//
+//	check-retry -sideeffects 'storj.io/common/events.Bus.Publish' ./...
 package main
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
 	"go/types"
+	"slices"
 	"strings"
 	"sync"
 
@@ -58,6 +81,7 @@ var extraFuncs string
 
 func init() {
 	Analyzer.Flags.StringVar(&extraFuncs, "funcs", "", "comma-separated list of additional retry function names to check")
+	Analyzer.Flags.StringVar(&extraSideEffects, "sideeffects", "", "comma-separated list of additional side-effecting functions, as pkgpath.Func or pkgpath.Type.Method, where * matches any part of a name")
 }
 
 // defaultRetryFuncNames lists the built-in function/method names that indicate a retrying pattern.
@@ -100,10 +124,10 @@ func run(pass *analysis.Pass) (any, error) {
 		(*ast.CallExpr)(nil),
 	}
 
-	inspect.Preorder(nodeFilter, func(n ast.Node) {
+	inspect.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
 		call := n.(*ast.CallExpr)
-		if !isRetryCall(pass, call) {
-			return
+		if !push || !isRetryCall(pass, call) {
+			return true
 		}
 
 		// Find the callback argument (a function literal) among all arguments.
@@ -112,9 +136,10 @@ func run(pass *analysis.Pass) (any, error) {
 			if !ok {
 				continue
 			}
-			checkCallback(pass, fn)
+			checkCallback(pass, fn, stack)
 			break
 		}
+		return true
 	})
 
 	return nil, nil
@@ -150,8 +175,9 @@ func isRetryCall(pass *analysis.Pass, call *ast.CallExpr) bool {
 }
 
 // checkCallback inspects a retry callback for outer-scope variables that are
-// modified but not reset at the top of the callback body.
-func checkCallback(pass *analysis.Pass, fn *ast.FuncLit) {
+// modified but not reset at the top of the callback body. The stack holds the
+// nodes enclosing the retry call.
+func checkCallback(pass *analysis.Pass, fn *ast.FuncLit, stack []ast.Node) {
 	if fn.Body == nil || len(fn.Body.List) == 0 {
 		return
 	}
@@ -159,6 +185,9 @@ func checkCallback(pass *analysis.Pass, fn *ast.FuncLit) {
 	// Collect the set of variables declared as callback parameters.
 	paramVars := collectParamVars(pass, fn)
 
+	// Report non-idempotent operations that are repeated on every attempt.
+	checkSideEffects(pass, fn, paramVars, enclosingParams(pass, stack))
+
 	// Find all outer-scope variables that are modified inside the callback.
 	modified := findModifiedOuterVars(pass, fn, paramVars)
 	if len(modified) == 0 {
@@ -168,16 +197,33 @@ func checkCallback(pass *analysis.Pass, fn *ast.FuncLit) {
 	// Find variables that are reset at the top of the callback.
 	reset := findResetVars(pass, fn)
 
-	// Report any modified-but-not-reset variables.
-	for obj, positions := range modified {
-		if reset[obj] {
-			continue
-		}
-		for _, pos := range positions {
-			if hasNolintDirective(pass, pos) {
+	// Collect any modified-but-not-reset variables.
+	unreset := map[types.Object][]modification{}
+	for obj, mods := range modified {
+		for _, mod := range mods {
+			if reset.covers(obj, mod.path) {
+				continue
+			}
+			if hasNolintDirective(pass, mod.pos) {
 				continue
 			}
-			pass.Reportf(pos, "variable %q is modified inside retry callback but not reset at the top of the callback", obj.Name())
+			unreset[obj] = append(unreset[obj], mod)
+		}
+	}
+	if len(unreset) == 0 {
+		return
+	}
+
+	// Every diagnostic in the callback shares the same fix, which resets all
+	// of the variables at once.
+	fixes := suggestResets(pass, fn, unreset)
+	for obj, mods := range unreset {
+		for _, mod := range mods {
+			pass.Report(analysis.Diagnostic{
+				Pos:            mod.pos,
+				Message:        fmt.Sprintf("variable %q is modified inside retry callback but not reset at the top of the callback", obj.Name()),
+				SuggestedFixes: fixes,
+			})
 		}
 	}
 }
@@ -206,11 +252,23 @@ func collectParamVars(pass *analysis.Pass, fn *ast.FuncLit) map[types.Object]boo
 	return params
 }
 
+// modification is a write to an outer variable, possibly through fields, map
+// or slice elements, or pointer dereferences.
+type modification struct {
+	pos  token.Pos
+	path accessPath
+}
+
 // findModifiedOuterVars walks the callback body and returns outer-scope variables
-// that are assigned or accumulated. It maps each variable object to the positions
-// where modifications occur.
-func findModifiedOuterVars(pass *analysis.Pass, fn *ast.FuncLit, paramVars map[types.Object]bool) map[types.Object][]token.Pos {
-	modified := map[types.Object][]token.Pos{}
+// that are assigned or accumulated. It maps each variable object to the
+// modifications made to it.
+func findModifiedOuterVars(pass *analysis.Pass, fn *ast.FuncLit, paramVars map[types.Object]bool) map[types.Object][]modification {
+	modified := map[types.Object][]modification{}
+	add := func(expr ast.Expr) {
+		if obj, path := outerAccess(pass, fn, expr, paramVars); obj != nil {
+			modified[obj] = append(modified[obj], modification{pos: expr.Pos(), path: path})
+		}
+	}
 
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
 		switch n := n.(type) {
@@ -222,35 +280,25 @@ func findModifiedOuterVars(pass *analysis.Pass, fn *ast.FuncLit, paramVars map[t
 			return true
 		case *ast.AssignStmt:
 			for _, lhs := range n.Lhs {
-				if obj := outerVar(pass, fn, lhs, paramVars); obj != nil {
-					modified[obj] = append(modified[obj], lhs.Pos())
-				}
+				add(lhs)
 			}
 		case *ast.IncDecStmt:
-			if obj := outerVar(pass, fn, n.X, paramVars); obj != nil {
-				modified[obj] = append(modified[obj], n.X.Pos())
-			}
+			add(n.X)
 		case *ast.RangeStmt:
 			// "for outerKey, outerVal = range items {}" assigns to outer vars.
 			if n.Tok == token.ASSIGN {
 				if n.Key != nil {
-					if obj := outerVar(pass, fn, n.Key, paramVars); obj != nil {
-						modified[obj] = append(modified[obj], n.Key.Pos())
-					}
+					add(n.Key)
 				}
 				if n.Value != nil {
-					if obj := outerVar(pass, fn, n.Value, paramVars); obj != nil {
-						modified[obj] = append(modified[obj], n.Value.Pos())
-					}
+					add(n.Value)
 				}
 			}
 		case *ast.ExprStmt:
 			// "delete(outerMap, key)" mutates the map.
 			if call, ok := n.X.(*ast.CallExpr); ok {
 				if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == "delete" && len(call.Args) == 2 {
-					if obj := outerVar(pass, fn, call.Args[0], paramVars); obj != nil {
-						modified[obj] = append(modified[obj], call.Args[0].Pos())
-					}
+					add(call.Args[0])
 				}
 			}
 		}
@@ -264,40 +312,45 @@ func findModifiedOuterVars(pass *analysis.Pass, fn *ast.FuncLit, paramVars map[t
 // inside the callback and not a parameter). Returns the variable's types.Object
 // or nil.
 func outerVar(pass *analysis.Pass, fn *ast.FuncLit, expr ast.Expr, paramVars map[types.Object]bool) types.Object {
-	// Unwrap index/selector expressions: m[k] = v → check m, s.field = v → check s.
-	expr = unwrapIndexAndSelector(expr)
+	obj, _ := outerAccess(pass, fn, expr, paramVars)
+	return obj
+}
 
-	ident, ok := expr.(*ast.Ident)
-	if !ok {
-		return nil
+// outerAccess is like outerVar, but also returns the access path from the outer
+// variable to the location written by expr, e.g. "out.Items" yields out and
+// [* Items] when out is a pointer.
+func outerAccess(pass *analysis.Pass, fn *ast.FuncLit, expr ast.Expr, paramVars map[types.Object]bool) (types.Object, accessPath) {
+	ident, path := splitAccess(pass, expr)
+	if ident == nil {
+		return nil, nil
 	}
 
 	obj := pass.TypesInfo.ObjectOf(ident)
 	if obj == nil {
-		return nil
+		return nil, nil
 	}
 
 	// Skip parameters.
 	if paramVars[obj] {
-		return nil
+		return nil, nil
 	}
 
 	// Skip blank identifiers.
 	if ident.Name == "_" {
-		return nil
+		return nil, nil
 	}
 
 	v, ok := obj.(*types.Var)
 	if !ok {
-		return nil
+		return nil, nil
 	}
 
 	// Check if the variable was declared inside the callback body.
 	if fn.Body.Pos() <= v.Pos() && v.Pos() < fn.Body.End() {
-		return nil
+		return nil, nil
 	}
 
-	return obj
+	return obj, path
 }
 
 // findResetVars scans the top-level statements of the callback body for
@@ -307,10 +360,17 @@ func outerVar(pass *analysis.Pass, fn *ast.FuncLit, expr ast.Expr, paramVars map
 // first statement that is neither a declaration nor a plain "=" assignment,
 // since resets should happen before other logic.
 //
+// Only resets of the whole root object count: either the variable itself
+// ("out = Result{}") or the value it points to ("*out = Result{}"). Such a
+// reset covers every modification made through fields, elements or pointer
+// dereferences of that object, e.g. "out.Items = append(out.Items, x)".
+// Writes through a pointer are only resets when they assign a composite
+// literal, so "*p = 42" is still a modification.
+//
 // Self-referential assignments (where the variable appears on the RHS) are NOT
 // considered resets, e.g. "rows = append(rows, 123)".
-func findResetVars(pass *analysis.Pass, fn *ast.FuncLit) map[types.Object]bool {
-	reset := map[types.Object]bool{}
+func findResetVars(pass *analysis.Pass, fn *ast.FuncLit) resetSet {
+	reset := resetSet{}
 
 	for _, stmt := range fn.Body.List {
 		switch s := stmt.(type) {
@@ -326,33 +386,12 @@ func findResetVars(pass *analysis.Pass, fn *ast.FuncLit) map[types.Object]bool {
 				// Compound assignments (+=, etc.) are not resets, stop scanning.
 				return reset
 			}
-
-			for _, lhs := range s.Lhs {
-				ident, ok := lhs.(*ast.Ident)
-				if !ok {
-					continue
-				}
-				obj := pass.TypesInfo.ObjectOf(ident)
-				if obj == nil {
-					continue
-				}
-				// Check that the RHS does not reference the same variable.
-				// For multi-value returns (e.g. "info, err = fn()"), len(Rhs)==1,
-				// so always check the single RHS expression.
-				if selfRef(pass, s, obj) {
-					continue
-				}
-				reset[obj] = true
-			}
+			reset.addAssign(pass, s)
 		case *ast.ExprStmt:
 			// Handle "clear(m)" as a reset for m.
 			if call, ok := s.X.(*ast.CallExpr); ok {
 				if ident, ok := call.Fun.(*ast.Ident); ok && ident.Name == "clear" && len(call.Args) == 1 {
-					if arg, ok := call.Args[0].(*ast.Ident); ok {
-						if obj := pass.TypesInfo.ObjectOf(arg); obj != nil {
-							reset[obj] = true
-						}
-					}
+					reset.add(pass, call.Args[0])
 				}
 			}
 			continue
@@ -363,20 +402,7 @@ func findResetVars(pass *analysis.Pass, fn *ast.FuncLit) map[types.Object]bool {
 			if !ok || assign.Tok != token.ASSIGN {
 				return reset
 			}
-			for _, lhs := range assign.Lhs {
-				ident, ok := lhs.(*ast.Ident)
-				if !ok {
-					continue
-				}
-				obj := pass.TypesInfo.ObjectOf(ident)
-				if obj == nil {
-					continue
-				}
-				if selfRef(pass, assign, obj) {
-					continue
-				}
-				reset[obj] = true
-			}
+			reset.addAssign(pass, assign)
 			return reset
 		default:
 			return reset
@@ -386,20 +412,113 @@ func findResetVars(pass *analysis.Pass, fn *ast.FuncLit) map[types.Object]bool {
 	return reset
 }
 
-// unwrapIndexAndSelector strips *ast.IndexExpr, *ast.SelectorExpr, and
-// *ast.StarExpr wrappers so that m[k] yields m, s.field yields s,
-// and *ptr yields ptr.
-func unwrapIndexAndSelector(expr ast.Expr) ast.Expr {
+// resetSet maps variables to the access paths that are reset at the top of
+// the callback.
+type resetSet map[types.Object][]accessPath
+
+// addAssign records the non-self-referential targets of assign as resets.
+func (reset resetSet) addAssign(pass *analysis.Pass, assign *ast.AssignStmt) {
+	for _, lhs := range assign.Lhs {
+		ident, path := splitAccess(pass, lhs)
+		if ident == nil {
+			continue
+		}
+		if len(path) > 0 && !overwritesValue(pass, lhs) {
+			continue
+		}
+		obj := pass.TypesInfo.ObjectOf(ident)
+		if obj == nil {
+			continue
+		}
+		// Check that the RHS does not reference the same variable.
+		// For multi-value returns (e.g. "info, err = fn()"), len(Rhs)==1,
+		// so always check the single RHS expression.
+		// "rows = rows[:0]" is the exception, it truncates the slice.
+		if selfRef(pass, assign, obj) && !isTruncation(pass, assign, obj) {
+			continue
+		}
+		reset.add(pass, lhs)
+	}
+}
+
+// overwritesValue reports whether a write through a pointer to lhs replaces
+// a whole value, e.g. "*out = Result{}" or "*out = zero", rather than a
+// scalar such as "*count = 0", which is a modification of the pointee.
+func overwritesValue(pass *analysis.Pass, lhs ast.Expr) bool {
+	typ := pass.TypesInfo.TypeOf(lhs)
+	if typ == nil {
+		return false
+	}
+	_, scalar := typ.Underlying().(*types.Basic)
+	return !scalar
+}
+
+// add records expr as reset when it denotes a whole root object.
+func (reset resetSet) add(pass *analysis.Pass, expr ast.Expr) {
+	ident, path := splitAccess(pass, expr)
+	if ident == nil || !(len(path) == 0 || slices.Equal(path, accessPath{"*"})) {
+		return
+	}
+	if obj := pass.TypesInfo.ObjectOf(ident); obj != nil {
+		reset[obj] = append(reset[obj], path)
+	}
+}
+
+// covers reports whether a modification of obj at path is covered by a reset.
+func (reset resetSet) covers(obj types.Object, path accessPath) bool {
+	for _, r := range reset[obj] {
+		if r.covers(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// accessPath is the chain of field selections (the field name), pointer
+// dereferences ("*") and element accesses ("[]") from a variable to the
+// location that is written. Implicit dereferences of pointers in field
+// selections are made explicit, so "p.x" and "(*p).x" have the same path.
+type accessPath []string
+
+// covers reports whether resetting p also resets q, i.e. p is a prefix of q.
+func (p accessPath) covers(q accessPath) bool {
+	return len(p) <= len(q) && slices.Equal(p, q[:len(p)])
+}
+
+// splitAccess splits expr into the root identifier and the access path from
+// it. Qualified identifiers ("pkg.Var") are treated as the root identifier.
+// It returns nil when expr is not rooted at an identifier, e.g. "f().x".
+func splitAccess(pass *analysis.Pass, expr ast.Expr) (*ast.Ident, accessPath) {
+	var path accessPath
 	for {
 		switch e := expr.(type) {
-		case *ast.IndexExpr:
+		case *ast.ParenExpr:
 			expr = e.X
-		case *ast.SelectorExpr:
+		case *ast.IndexExpr:
+			path = append(path, "[]")
 			expr = e.X
 		case *ast.StarExpr:
+			path = append(path, "*")
 			expr = e.X
+		case *ast.SelectorExpr:
+			if x, ok := e.X.(*ast.Ident); ok {
+				if _, ok := pass.TypesInfo.ObjectOf(x).(*types.PkgName); ok {
+					slices.Reverse(path)
+					return e.Sel, path
+				}
+			}
+			path = append(path, e.Sel.Name)
+			if typ := pass.TypesInfo.TypeOf(e.X); typ != nil {
+				if _, ok := typ.Underlying().(*types.Pointer); ok {
+					path = append(path, "*")
+				}
+			}
+			expr = e.X
+		case *ast.Ident:
+			slices.Reverse(path)
+			return e, path
 		default:
-			return expr
+			return nil, nil
 		}
 	}
 }
@@ -446,6 +565,30 @@ func selfRef(pass *analysis.Pass, assign *ast.AssignStmt, obj types.Object) bool
 	return false
 }
 
+// isTruncation reports whether assign is "x = x[:0]" for obj.
+func isTruncation(pass *analysis.Pass, assign *ast.AssignStmt, obj types.Object) bool {
+	if len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return false
+	}
+	slice, ok := ast.Unparen(assign.Rhs[0]).(*ast.SliceExpr)
+	if !ok || slice.Slice3 || slice.High == nil {
+		return false
+	}
+	if x, ok := ast.Unparen(slice.X).(*ast.Ident); !ok || pass.TypesInfo.ObjectOf(x) != obj {
+		return false
+	}
+	if slice.Low != nil && !isZeroConst(pass, slice.Low) {
+		return false
+	}
+	return isZeroConst(pass, slice.High)
+}
+
+// isZeroConst reports whether expr is a constant equal to zero.
+func isZeroConst(pass *analysis.Pass, expr ast.Expr) bool {
+	tv, ok := pass.TypesInfo.Types[expr]
+	return ok && tv.Value != nil && tv.Value.String() == "0"
+}
+
 // exprReferences reports whether expr contains a reference to obj.
 // It does not descend into nested function literals, because a reference
 // inside a closure argument (e.g. CollectRow(..., func() { err = ... }))
@@ -0,0 +1,156 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// suggestResets returns a fix that inserts a reset for every variable in
+// unreset as the first statements of the callback. Variables whose type cannot
+// be spelled in the current file are left out; when none remain, it returns nil.
+func suggestResets(pass *analysis.Pass, fn *ast.FuncLit, unreset map[types.Object][]modification) []analysis.SuggestedFix {
+	objs := make([]types.Object, 0, len(unreset))
+	for obj := range unreset {
+		objs = append(objs, obj)
+	}
+	slices.SortFunc(objs, func(a, b types.Object) int { return int(a.Pos() - b.Pos()) })
+
+	file := enclosingFile(pass, fn)
+	var stmts []string
+	for _, obj := range objs {
+		if stmt, ok := resetStmt(pass, file, obj, unreset[obj]); ok {
+			stmts = append(stmts, stmt)
+		}
+	}
+	if len(stmts) == 0 {
+		return nil
+	}
+
+	first := fn.Body.List[0]
+	indent := strings.Repeat("\t", pass.Fset.Position(first.Pos()).Column-1)
+	text := strings.Join(stmts, "\n"+indent) + "\n" + indent
+
+	return []analysis.SuggestedFix{{
+		Message: "Reset modified variables at the top of the callback",
+		TextEdits: []analysis.TextEdit{
+			{Pos: first.Pos(), End: first.Pos(), NewText: []byte(text)},
+		},
+	}}
+}
+
+// resetStmt returns the statement that resets obj based on its type:
+// "clear(m)" for maps, "s = s[:0]" for slices, "*p = T{}" for pointers that
+// are only written through, and an assignment of the zero value otherwise.
+func resetStmt(pass *analysis.Pass, file *ast.File, obj types.Object, mods []modification) (string, bool) {
+	name := obj.Name()
+	typ := obj.Type()
+
+	if _, ok := typ.Underlying().(*types.Pointer); ok && allThroughPointer(mods) {
+		zero, ok := zeroValue(pass, file, typ.Underlying().(*types.Pointer).Elem())
+		return "*" + name + " = " + zero, ok
+	}
+
+	switch typ.Underlying().(type) {
+	case *types.Map:
+		return "clear(" + name + ")", true
+	case *types.Slice:
+		return name + " = " + name + "[:0]", true
+	}
+
+	zero, ok := zeroValue(pass, file, typ)
+	return name + " = " + zero, ok
+}
+
+// allThroughPointer reports whether every modification dereferences the
+// variable rather than reassigning it.
+func allThroughPointer(mods []modification) bool {
+	for _, mod := range mods {
+		if len(mod.path) == 0 || mod.path[0] != "*" {
+			return false
+		}
+	}
+	return true
+}
+
+// zeroValue returns the zero value expression of typ as written in file.
+func zeroValue(pass *analysis.Pass, file *ast.File, typ types.Type) (string, bool) {
+	switch u := typ.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return "false", true
+		case u.Info()&types.IsString != 0:
+			return `""`, true
+		case u.Info()&types.IsNumeric != 0:
+			return "0", true
+		case u.Kind() == types.UnsafePointer:
+			return "nil", true
+		}
+		return "", false
+	case *types.Pointer, *types.Slice, *types.Map, *types.Chan, *types.Signature:
+		return "nil", true
+	case *types.Interface:
+		if _, ok := typ.(*types.TypeParam); !ok {
+			return "nil", true
+		}
+	}
+
+	// Structs, arrays and type parameters need the type to be spelled out.
+	spellable := true
+	name := types.TypeString(typ, func(pkg *types.Package) string {
+		if pkg == pass.Pkg {
+			return ""
+		}
+		if local, ok := importName(file, pkg); ok {
+			return local
+		}
+		spellable = false
+		return pkg.Name()
+	})
+	if !spellable {
+		return "", false
+	}
+	if _, ok := typ.(*types.TypeParam); ok {
+		return "*new(" + name + ")", true
+	}
+	return name + "{}", true
+}
+
+// importName returns the name under which pkg is imported in file.
+func importName(file *ast.File, pkg *types.Package) (string, bool) {
+	if file == nil {
+		return "", false
+	}
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || path != pkg.Path() {
+			continue
+		}
+		if spec.Name == nil {
+			return pkg.Name(), true
+		}
+		if spec.Name.Name == "_" || spec.Name.Name == "." {
+			return "", spec.Name.Name == "."
+		}
+		return spec.Name.Name, true
+	}
+	return "", false
+}
+
+// enclosingFile returns the file that contains node.
+func enclosingFile(pass *analysis.Pass, node ast.Node) *ast.File {
+	for _, f := range pass.Files {
+		if f.FileStart <= node.Pos() && node.Pos() < f.FileEnd {
+			return f
+		}
+	}
+	return nil
+}
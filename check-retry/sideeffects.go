@@ -0,0 +1,182 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"path"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+var extraSideEffects string
+
+// defaultSideEffects lists the built-in functions and methods that must not be
+// repeated by a retried callback. Methods are written as pkgpath.Type.Method and
+// "*" matches any part of a name, see path.Match.
+var defaultSideEffects = []string{
+	"sync/atomic.Add*",
+	"sync/atomic.*.Add",
+	"github.com/spacemonkeygo/monkit/v3.Counter.Inc",
+	"github.com/spacemonkeygo/monkit/v3.Counter.Dec",
+	"github.com/spacemonkeygo/monkit/v3.Meter.Mark",
+	"github.com/spacemonkeygo/monkit/v3.Meter.Mark64",
+	"go.uber.org/zap.Logger.Error",
+	"go.uber.org/zap.SugaredLogger.Error*",
+}
+
+var (
+	sideEffectsOnce sync.Once
+	sideEffects     []string
+)
+
+// mergedSideEffects returns the combined list of default and user-specified
+// side-effecting function patterns.
+func mergedSideEffects() []string {
+	sideEffectsOnce.Do(func() {
+		sideEffects = append(sideEffects, defaultSideEffects...)
+		if extraSideEffects != "" {
+			for _, name := range strings.Split(extraSideEffects, ",") {
+				name = strings.TrimSpace(name)
+				if name != "" {
+					sideEffects = append(sideEffects, name)
+				}
+			}
+		}
+	})
+	return sideEffects
+}
+
+// checkSideEffects reports operations inside the callback that are not
+// idempotent and therefore are repeated on every retry. outerParams are the
+// parameters of the functions enclosing the callback, whose function values
+// are the callbacks that must not be repeated.
+func checkSideEffects(pass *analysis.Pass, fn *ast.FuncLit, paramVars, outerParams map[types.Object]bool) {
+	report := func(node ast.Node, what string) {
+		if hasNolintDirective(pass, node.Pos()) {
+			return
+		}
+		pass.Reportf(node.Pos(), "%s inside retry callback is repeated on every retry", what)
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SendStmt:
+			if obj := outerVar(pass, fn, n.Chan, paramVars); obj != nil {
+				report(n, fmt.Sprintf("send to channel %q", obj.Name()))
+			}
+		case *ast.CallExpr:
+			if what := sideEffectCall(pass, fn, n, paramVars, outerParams); what != "" {
+				report(n, what)
+			}
+		}
+		return true
+	})
+}
+
+// sideEffectCall returns a description of call when it is a non-idempotent
+// operation, otherwise an empty string.
+func sideEffectCall(pass *analysis.Pass, fn *ast.FuncLit, call *ast.CallExpr, paramVars, outerParams map[types.Object]bool) string {
+	fun := ast.Unparen(call.Fun)
+
+	// "close(outerChan)" can only happen once.
+	if ident, ok := fun.(*ast.Ident); ok && ident.Name == "close" && len(call.Args) == 1 {
+		if _, ok := pass.TypesInfo.ObjectOf(ident).(*types.Builtin); ok {
+			if obj := outerVar(pass, fn, call.Args[0], paramVars); obj != nil {
+				return fmt.Sprintf("close of channel %q", obj.Name())
+			}
+			return ""
+		}
+	}
+
+	if callee := typeutil.StaticCallee(pass.TypesInfo, call); callee != nil {
+		name := funcName(callee)
+		for _, pattern := range mergedSideEffects() {
+			if ok, _ := path.Match(pattern, name); ok {
+				return fmt.Sprintf("call to %s", name)
+			}
+		}
+		return ""
+	}
+
+	// Calling a callback that was passed into the enclosing function, either
+	// directly or as a field of a parameter. Other function values, such as
+	// local helper closures, are assumed to be safe to repeat.
+	typ := pass.TypesInfo.TypeOf(fun)
+	if typ == nil {
+		return ""
+	}
+	if _, ok := typ.Underlying().(*types.Signature); !ok {
+		return ""
+	}
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		if !outerParams[pass.TypesInfo.ObjectOf(fun)] {
+			return ""
+		}
+	case *ast.SelectorExpr:
+		selection := pass.TypesInfo.Selections[fun]
+		if selection == nil || selection.Kind() != types.FieldVal {
+			return ""
+		}
+		root, _ := splitAccess(pass, fun)
+		if root == nil || !outerParams[pass.TypesInfo.ObjectOf(root)] {
+			return ""
+		}
+	default:
+		return ""
+	}
+	return fmt.Sprintf("call to outer callback %q", types.ExprString(fun))
+}
+
+// enclosingParams returns the parameters and receivers of the functions in
+// the stack.
+func enclosingParams(pass *analysis.Pass, stack []ast.Node) map[types.Object]bool {
+	params := map[types.Object]bool{}
+	add := func(fields *ast.FieldList) {
+		if fields == nil {
+			return
+		}
+		for _, field := range fields.List {
+			for _, name := range field.Names {
+				if obj := pass.TypesInfo.ObjectOf(name); obj != nil {
+					params[obj] = true
+				}
+			}
+		}
+	}
+	for _, node := range stack {
+		switch node := node.(type) {
+		case *ast.FuncDecl:
+			add(node.Recv)
+			add(node.Type.Params)
+		case *ast.FuncLit:
+			add(node.Type.Params)
+		}
+	}
+	return params
+}
+
+// funcName returns the name of fn as matched against the side-effect patterns:
+// pkgpath.Func for functions and pkgpath.Type.Method for methods.
+func funcName(fn *types.Func) string {
+	if fn.Pkg() == nil {
+		return fn.Name()
+	}
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		t := types.Unalias(recv.Type())
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = types.Unalias(ptr.Elem())
+		}
+		if named, ok := t.(*types.Named); ok {
+			return fn.Pkg().Path() + "." + named.Obj().Name() + "." + fn.Name()
+		}
+	}
+	return fn.Pkg().Path() + "." + fn.Name()
+}
@@ -463,6 +463,15 @@ func badPointerDeref() {
 	_ = p
 }
 
+func badPointerDerefCompound() {
+	var x int
+	p := &x
+	WithRetry(func() {
+		*p += 42 // want `variable "p" is modified inside retry callback but not reset at the top of the callback`
+	})
+	_ = p
+}
+
 // --- Bad: delete on outer map ---
 
 func badDeleteOuterMap() {
@@ -550,3 +559,88 @@ func notRetry(fn func())                                                   { fn(
 func work2(tx int) (int, error)                                            { return tx, nil }
 func CollectRowWithCallback(tx int, fn func(item *int) error) (int, error) { return tx, fn(nil) }
 func decodeRow(item *int) error                                            { return nil }
+
+// --- Writes through fields, map elements and pointers ---
+
+type page struct {
+	Items []int
+	Names map[string]int
+}
+
+func badFieldAppend() {
+	var result page
+	WithRetry(func() {
+		result.Items = append(result.Items, 1) // want `variable "result" is modified inside retry callback but not reset at the top of the callback`
+	})
+	_ = result
+}
+
+func badPointerFieldAppend(out *page) {
+	WithRetry(func() {
+		out.Items = append(out.Items, 1) // want `variable "out" is modified inside retry callback but not reset at the top of the callback`
+	})
+}
+
+func badNestedMapWrite(out *page) {
+	WithRetry(func() {
+		out.Names["a"]++ // want `variable "out" is modified inside retry callback but not reset at the top of the callback`
+	})
+}
+
+func badParenDeref(out *page) {
+	WithRetry(func() {
+		(*out).Items = nil // want `variable "out" is modified inside retry callback but not reset at the top of the callback`
+	})
+}
+
+func badFieldResetOnly() {
+	var result page
+	WithRetry(func() {
+		result.Items = nil                     // want `variable "result" is modified inside retry callback but not reset at the top of the callback`
+		result.Items = append(result.Items, 1) // want `variable "result" is modified inside retry callback but not reset at the top of the callback`
+	})
+	_ = result
+}
+
+func goodPointerDerefReset(out *page) {
+	WithRetry(func() {
+		*out = page{}
+		out.Items = append(out.Items, 1)
+		out.Names["a"]++
+	})
+}
+
+var zeroPage page
+
+func newPage() page { return page{Names: map[string]int{}} }
+
+func goodPointerDerefResetVar(out *page) {
+	WithRetry(func() {
+		*out = zeroPage
+		out.Items = append(out.Items, 1)
+	})
+}
+
+func goodPointerDerefResetCall(out *page) {
+	WithRetry(func() {
+		*out = newPage()
+		out.Names["a"]++
+	})
+}
+
+func badPointerDerefResetSelf(out *page) {
+	WithRetry(func() {
+		*out = page{Items: out.Items}    // want `variable "out" is modified inside retry callback but not reset at the top of the callback`
+		out.Items = append(out.Items, 1) // want `variable "out" is modified inside retry callback but not reset at the top of the callback`
+	})
+}
+
+func goodRootResetCoversFields() {
+	var result page
+	WithRetry(func() {
+		result = page{Names: map[string]int{}}
+		result.Items = append(result.Items, 1)
+		result.Names["a"] = 1
+	})
+	_ = result
+}
@@ -0,0 +1,169 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package a
+
+import (
+	"sync/atomic"
+
+	monkit "github.com/spacemonkeygo/monkit/v3"
+	"go.uber.org/zap"
+
+	"hooks"
+)
+
+var mon = monkit.Package()
+
+// --- Bad: side effects repeated on every retry ---
+
+func badChannelSend() {
+	results := make(chan int, 1)
+	WithRetry(func() {
+		results <- doWork() // want `send to channel "results" inside retry callback is repeated on every retry`
+	})
+}
+
+func badChannelClose() {
+	done := make(chan struct{})
+	WithRetry(func() {
+		close(done) // want `close of channel "done" inside retry callback is repeated on every retry`
+	})
+}
+
+func badAtomicAdd() {
+	var processed int64
+	WithRetry(func() {
+		atomic.AddInt64(&processed, 1) // want `call to sync/atomic.AddInt64 inside retry callback is repeated on every retry`
+	})
+}
+
+func badTypedAtomicAdd() {
+	var processed atomic.Int64
+	WithRetry(func() {
+		processed.Add(1) // want `call to sync/atomic.Int64.Add inside retry callback is repeated on every retry`
+	})
+}
+
+func badMonkitCounter() {
+	WithRetry(func() {
+		mon.Counter("attempts").Inc(1) // want `call to github.com/spacemonkeygo/monkit/v3.Counter.Inc inside retry callback is repeated on every retry`
+	})
+}
+
+func badErrorLog(log *zap.Logger) {
+	WithRetry(func() {
+		log.Error("failed") // want `call to go.uber.org/zap.Logger.Error inside retry callback is repeated on every retry`
+	})
+}
+
+func badSugaredErrorLog(log *zap.SugaredLogger) {
+	WithRetry(func() {
+		log.Errorf("failed %d", 1) // want `call to go.uber.org/zap.SugaredLogger.Errorf inside retry callback is repeated on every retry`
+	})
+}
+
+func badOuterCallback(onItem func(int)) {
+	r := Retrier{}
+	r.WithTx(func(tx int) error {
+		onItem(tx) // want `call to outer callback "onItem" inside retry callback is repeated on every retry`
+		return nil
+	})
+}
+
+type handlers struct {
+	onDone func()
+}
+
+func badOuterCallbackField(h handlers) {
+	WithRetry(func() {
+		h.onDone() // want `call to outer callback "h.onDone" inside retry callback is repeated on every retry`
+	})
+}
+
+type config struct {
+	hooks handlers
+}
+
+func badOuterCallbackNestedField(cfg *config) {
+	WithRetry(func() {
+		cfg.hooks.onDone() // want `call to outer callback "cfg.hooks.onDone" inside retry callback is repeated on every retry`
+	})
+}
+
+func (h *handlers) badOuterCallbackReceiver() {
+	WithRetry(func() {
+		h.onDone() // want `call to outer callback "h.onDone" inside retry callback is repeated on every retry`
+	})
+}
+
+func badSideEffectInNestedClosure() {
+	results := make(chan int, 1)
+	WithRetry(func() {
+		forEach(func() {
+			results <- 1 // want `send to channel "results" inside retry callback is repeated on every retry`
+		})
+	})
+}
+
+// --- Good: idempotent or local operations ---
+
+func goodLocalChannel() {
+	WithRetry(func() {
+		local := make(chan int, 1)
+		local <- 1
+		close(local)
+	})
+}
+
+func goodInfoLog(log *zap.Logger) {
+	WithRetry(func() {
+		log.Info("attempt")
+	})
+}
+
+func goodAtomicLoad() {
+	var processed int64
+	WithRetry(func() {
+		_ = atomic.LoadInt64(&processed)
+	})
+}
+
+func goodLocalCallback() {
+	WithRetry(func() {
+		fn := func() {}
+		fn()
+	})
+}
+
+func goodOuterHelperClosure() error {
+	var rows []int
+	scan := func(row int) error {
+		rows = append(rows, row)
+		return nil
+	}
+	r := Retrier{}
+	return r.WithTx(func(tx int) error {
+		rows = nil
+		return scan(tx)
+	})
+}
+
+func goodLocalHandlers() {
+	h := handlers{onDone: func() {}}
+	WithRetry(func() {
+		h.onDone()
+	})
+}
+
+func goodPackageHook() {
+	WithRetry(func() {
+		hooks.Hook()
+	})
+}
+
+func goodSideEffectIgnored() {
+	done := make(chan struct{})
+	WithRetry(func() {
+		close(done) //check-retry:ignore
+	})
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package fix
+
+// WithRetry calls fn, potentially multiple times on failure.
+func WithRetry(fn func()) { fn() }
+
+type page struct {
+	Items []int
+	Names map[string]int
+}
+
+func badCounters() {
+	var total int
+	var name string
+	var done bool
+	WithRetry(func() {
+		total++      // want `variable "total" is modified inside retry callback but not reset at the top of the callback`
+		name += "x"  // want `variable "name" is modified inside retry callback but not reset at the top of the callback`
+		done = !done // want `variable "done" is modified inside retry callback but not reset at the top of the callback`
+	})
+	_, _, _ = total, name, done
+}
+
+func badCollections() {
+	var rows []int
+	seen := map[string]bool{}
+	WithRetry(func() {
+		rows = append(rows, 1) // want `variable "rows" is modified inside retry callback but not reset at the top of the callback`
+		seen["a"] = true       // want `variable "seen" is modified inside retry callback but not reset at the top of the callback`
+	})
+	_, _ = rows, seen
+}
+
+func badStruct() {
+	var result page
+	WithRetry(func() {
+		result.Items = append(result.Items, 1) // want `variable "result" is modified inside retry callback but not reset at the top of the callback`
+	})
+	_ = result
+}
+
+func badPointer(out *page) {
+	WithRetry(func() {
+		out.Items = append(out.Items, 1) // want `variable "out" is modified inside retry callback but not reset at the top of the callback`
+	})
+}
+
+func goodTruncated() {
+	var rows []int
+	WithRetry(func() {
+		rows = rows[:0]
+		rows = append(rows, 1)
+	})
+	_ = rows
+}
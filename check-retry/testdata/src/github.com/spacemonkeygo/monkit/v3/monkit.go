@@ -0,0 +1,23 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package monkit is a minimal stub of github.com/spacemonkeygo/monkit/v3 for tests.
+package monkit
+
+// Scope is a named collection of metrics.
+type Scope struct{}
+
+// Package returns the scope for the calling package.
+func Package() *Scope { return &Scope{} }
+
+// Counter returns the named counter.
+func (s *Scope) Counter(name string) *Counter { return &Counter{} }
+
+// Counter keeps track of a running total.
+type Counter struct{}
+
+// Inc increments the counter.
+func (c *Counter) Inc(delta int64) {}
+
+// Current returns the current value.
+func (c *Counter) Current() int64 { return 0 }
@@ -0,0 +1,29 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package zap is a minimal stub of go.uber.org/zap for tests.
+package zap
+
+// Field is a logging field.
+type Field struct{}
+
+// Logger is a structured logger.
+type Logger struct{}
+
+// Info logs at info level.
+func (log *Logger) Info(msg string, fields ...Field) {}
+
+// Error logs at error level.
+func (log *Logger) Error(msg string, fields ...Field) {}
+
+// Sugar returns a sugared logger.
+func (log *Logger) Sugar() *SugaredLogger { return &SugaredLogger{} }
+
+// SugaredLogger is a loosely typed logger.
+type SugaredLogger struct{}
+
+// Errorf logs a formatted message at error level.
+func (s *SugaredLogger) Errorf(template string, args ...interface{}) {}
+
+// Infof logs a formatted message at info level.
+func (s *SugaredLogger) Infof(template string, args ...interface{}) {}
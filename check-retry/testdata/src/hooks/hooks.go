@@ -0,0 +1,7 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package hooks
+
+// Hook is called by the tests.
+var Hook = func() {}
@@ -1,7 +1,7 @@
 // Copyright (C) 2026 Storj Labs, Inc.
 // See LICENSE for copying information.
 
-// Package main implements a linter that validates zap logger field names.
+// Package main implements a linter that validates zap logger field names and messages.
 //
 // # Overview
 //
@@ -36,6 +36,33 @@
 //	logger.Error("error", zap.Int("Status-Code", 500))      // uppercase and dash
 //	logger.Debug("debug", zap.Duration("_elapsed", dur))    // leading underscore
 //
+// # Sensitive Data
+//
+// Logging secrets is reported:
+//   - Field names ending with one of the -sensitive-names words, e.g. password,
+//     secret, token, api_key, access_grant or serialized_key, optionally in
+//     plural. Words are matched between underscores, so user_api_key and
+//     auth_tokens match, but tokenizer and token_count don't.
+//   - Values of one of the -sensitive-types, e.g. macaroon.APIKey or grant.Access,
+//     also through pointers.
+//   - Values encoded with reflection (zap.Any, zap.Reflect, slog.Any and loosely
+//     typed key/value pairs) of structs with exported fields that are sensitive
+//     by name or type.
+//
+// Both flags take comma-separated lists that replace the defaults:
+//
+//	check-zap-fields -sensitive-types 'storj.io/common/macaroon.APIKey,storj.io/common/storj.NodeURL' ./...
+//
+// # Auto-fixing
+//
+// Invalid names come with a suggested fix that rewrites them, so whole codebases
+// can be migrated with -fix:
+//
+//	check-zap-fields -fix ./...
+//
+// When the name comes from a constant declared in the same package, the fix
+// edits the constant's declaration instead of the call.
+//
 // # Ignoring Violations
 //
 // There are three ways to ignore linter violations when necessary:
@@ -65,6 +92,50 @@
 //	// All zap field violations in this file will be ignored
 //
 // NOTE that the directive must not have a space after //.
+//
+// # Key/Value Pairs
+//
+// Keys passed as loosely typed key/value pairs are validated like field names,
+// both for zap.SugaredLogger (Debugw, Infow, Warnw, Errorw, DPanicw, Panicw,
+// Fatalw and With) and for log/slog (Debug, Info, Warn, Error, their Context
+// variants, Log, With and Group, as functions and as slog.Logger methods). The
+// log/slog attribute constructors (String, Int, Int64, Uint64, Float64, Bool,
+// Time, Duration, Any and Group) are checked like the zap field functions.
+//
+// Lists with an odd number of elements and keys that are not strings are
+// reported as well:
+//
+//	sugar.Infow("message", "User ID", id)          // invalid field name
+//	sugar.Infow("message", "user_id")              // missing value
+//	slog.Info("message", 42, id)                   // non-string key
+//	slog.Info("message", "user_id", id)            // valid
+//
+// # Logger Messages
+//
+// Messages passed to the zap.Logger methods (Debug, Info, Warn, Error, DPanic,
+// Panic and Fatal) must be constant strings without a trailing period, an
+// ellipsis is fine. Variable data belongs in fields:
+//
+//	logger.Info(fmt.Sprintf("deleted %d", n))            // built with fmt.Sprintf
+//	logger.Info("failed: " + err.Error())                // not constant
+//	logger.Info("deleted.")                              // trailing period
+//	logger.Info("deleted", zap.Int("count", n))          // valid
+//
+// # Field Name Consistency
+//
+// The same concept must be logged under the same field name within a module.
+// The linter gathers the field names of every package through analysis facts
+// and reports names that differ from a more common one only in underscores, an
+// id suffix or by a small edit distance (see -key-distance), e.g. nodeid and
+// node are reported when node_id is used more often. Names differing in the
+// plural or tense of a word, such as limit and limits or expired and expires,
+// name different things and are not reported. The suggested fix replaces the
+// name with the canonical one.
+//
+// Facts only flow from the imported packages, so a package is compared with
+// the packages of the module that it depends on, directly or indirectly, but
+// not with sibling packages that it doesn't import.
+//
 // # Detected Zap Field Functions
 //
 // The linter checks field names in the following zap functions:
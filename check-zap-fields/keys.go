@@ -0,0 +1,248 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+var keyDistance int
+
+func init() {
+	Analyzer.Flags.IntVar(&keyDistance, "key-distance", 1, "maximum edit distance between field names of at least 5 characters to report them as the same concept; names differing only in underscores or an id suffix are always reported")
+}
+
+// keysFact records how often each valid zap field name is used in a package.
+type keysFact struct {
+	Counts map[string]int
+}
+
+// AFact implements analysis.Fact.
+func (*keysFact) AFact() {}
+
+// String implements fmt.Stringer.
+func (f *keysFact) String() string {
+	keys := make([]string, 0, len(f.Counts))
+	for key, count := range f.Counts {
+		keys = append(keys, fmt.Sprintf("%s:%d", key, count))
+	}
+	slices.Sort(keys)
+	return strings.Join(keys, " ")
+}
+
+// keyUsage collects the valid field names used in a package.
+type keyUsage struct {
+	counts map[string]int
+	uses   []keyUse
+}
+
+// keyUse is a single field name literal that may be reported.
+type keyUse struct {
+	lit   *ast.BasicLit
+	key   string
+	label string
+}
+
+func newKeyUsage() *keyUsage {
+	return &keyUsage{counts: map[string]int{}}
+}
+
+// collect records the field name expr when it's a valid literal.
+func (k *keyUsage) collect(pass *analysis.Pass, expr ast.Expr, label string, ignoredFiles map[string]struct{}) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return
+	}
+	key, err := strconv.Unquote(lit.Value)
+	if err != nil || !rxValidFieldName.MatchString(key) {
+		// Invalid names are reported by checkNameLiteral.
+		return
+	}
+
+	k.counts[key]++
+
+	pos := pass.Fset.Position(lit.Pos())
+	if _, ok := ignoredFiles[pos.Filename]; ok {
+		return
+	}
+	if hasIgnoreDirective(pass, lit.Pos()) {
+		return
+	}
+	k.uses = append(k.uses, keyUse{lit: lit, key: key, label: label})
+}
+
+// checkKeyConsistency exports the field names used in the package and reports
+// the names that are used for the same concept as a more common name in the
+// module. Only the packages of the module that are dependencies of the package
+// are known through facts.
+func checkKeyConsistency(pass *analysis.Pass, keys *keyUsage) {
+	if len(keys.counts) > 0 {
+		pass.ExportPackageFact(&keysFact{Counts: keys.counts})
+	}
+
+	counts := map[string]int{}
+	for key, count := range keys.counts {
+		counts[key] += count
+	}
+	for _, fact := range pass.AllPackageFacts() {
+		if fact.Package == pass.Pkg || !inModule(pass, fact.Package) {
+			continue
+		}
+		for key, count := range fact.Fact.(*keysFact).Counts {
+			counts[key] += count
+		}
+	}
+
+	for _, use := range keys.uses {
+		canonical := canonicalKey(use.key, counts)
+		if canonical == use.key {
+			continue
+		}
+
+		pass.Report(analysis.Diagnostic{
+			Message: fmt.Sprintf(
+				"%s field name %s is inconsistent with %s used elsewhere in the module",
+				use.label, use.key, canonical),
+			Pos: use.lit.Pos(),
+			SuggestedFixes: []analysis.SuggestedFix{
+				{
+					Message: fmt.Sprintf("Replace with %s", canonical),
+					TextEdits: []analysis.TextEdit{
+						// Add one to start and subtract one to end to keep the double quote of the string literal.
+						{Pos: use.lit.Pos() + 1, End: use.lit.End() - 1, NewText: []byte(canonical)},
+					},
+				},
+			},
+		})
+	}
+}
+
+// inModule reports whether pkg belongs to the module being analyzed. When the
+// module is unknown, all packages are considered.
+func inModule(pass *analysis.Pass, pkg *types.Package) bool {
+	if pass.Module == nil || pass.Module.Path == "" {
+		return true
+	}
+	return pkg.Path() == pass.Module.Path || strings.HasPrefix(pkg.Path(), pass.Module.Path+"/")
+}
+
+// canonicalKey returns the most used field name that names the same concept
+// as key. Ties are broken alphabetically, so that all the similar names agree
+// on the same canonical one.
+func canonicalKey(key string, counts map[string]int) string {
+	best := key
+	for other, count := range counts {
+		if other == key || !similarKeys(key, other) {
+			continue
+		}
+		if count > counts[best] || (count == counts[best] && other < best) {
+			best = other
+		}
+	}
+	return best
+}
+
+// similarKeys reports whether a and b likely name the same concept, e.g.
+// node_id, nodeid and node.
+func similarKeys(a, b string) bool {
+	if distinctInflections(a, b) {
+		return false
+	}
+	a, b = keyStem(a), keyStem(b)
+	if a == b {
+		return true
+	}
+	if min(len(a), len(b)) < 5 {
+		return false
+	}
+	return editDistance(a, b) <= keyDistance
+}
+
+// inflectionSuffixes are the plural and tense endings of English words.
+var inflectionSuffixes = []string{"", "s", "es", "d", "ed", "ing"}
+
+// distinctInflections reports whether a and b differ only in the plural or
+// tense of one word, e.g. limit and limits or expired and expires, which name
+// different things. A plural modifier, as in segments_count and segment_count,
+// still names the same concept.
+func distinctInflections(a, b string) bool {
+	wordsA, wordsB := strings.Split(a, "_"), strings.Split(b, "_")
+	if len(wordsA) != len(wordsB) {
+		return false
+	}
+
+	differing := -1
+	for i := range wordsA {
+		if wordsA[i] == wordsB[i] {
+			continue
+		}
+		if differing >= 0 {
+			return false
+		}
+		differing = i
+	}
+	if differing < 0 {
+		return false
+	}
+
+	wordA, wordB := wordsA[differing], wordsB[differing]
+	if differing < len(wordsA)-1 && (wordA+"s" == wordB || wordB+"s" == wordA) {
+		return false
+	}
+	return inflected(wordA, wordB)
+}
+
+// inflected reports whether a and b are forms of the same word.
+func inflected(a, b string) bool {
+	for _, suffixA := range inflectionSuffixes {
+		base, ok := strings.CutSuffix(a, suffixA)
+		if !ok || len(base) < 3 {
+			continue
+		}
+		for _, suffixB := range inflectionSuffixes {
+			if suffixA != suffixB && base+suffixB == b {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// keyStem removes underscores and an id suffix from key.
+func keyStem(key string) string {
+	key = strings.ReplaceAll(key, "_", "")
+	if stem := strings.TrimSuffix(key, "id"); len(stem) >= 4 {
+		return stem
+	}
+	return key
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// isSlogAttrFunction checks if the function is a log/slog attribute constructor.
+func isSlogAttrFunction(fn *types.Func) bool {
+	switch fn.FullName() {
+	case "log/slog.String",
+		"log/slog.Int",
+		"log/slog.Int64",
+		"log/slog.Uint64",
+		"log/slog.Float64",
+		"log/slog.Bool",
+		"log/slog.Time",
+		"log/slog.Duration",
+		"log/slog.Any",
+		"log/slog.Group":
+		return true
+	}
+	return false
+}
+
+// keyValueStart checks if the function takes loosely typed key/value pairs and
+// returns the index of the argument where they start.
+func keyValueStart(fn *types.Func) (int, bool) {
+	switch fn.FullName() {
+	case "(*go.uber.org/zap.SugaredLogger).Debugw",
+		"(*go.uber.org/zap.SugaredLogger).Infow",
+		"(*go.uber.org/zap.SugaredLogger).Warnw",
+		"(*go.uber.org/zap.SugaredLogger).Errorw",
+		"(*go.uber.org/zap.SugaredLogger).DPanicw",
+		"(*go.uber.org/zap.SugaredLogger).Panicw",
+		"(*go.uber.org/zap.SugaredLogger).Fatalw":
+		return 1, true
+	case "(*go.uber.org/zap.SugaredLogger).With":
+		return 0, true
+
+	case "log/slog.Debug", "(*log/slog.Logger).Debug",
+		"log/slog.Info", "(*log/slog.Logger).Info",
+		"log/slog.Warn", "(*log/slog.Logger).Warn",
+		"log/slog.Error", "(*log/slog.Logger).Error",
+		"log/slog.Group":
+		return 1, true
+	case "log/slog.DebugContext", "(*log/slog.Logger).DebugContext",
+		"log/slog.InfoContext", "(*log/slog.Logger).InfoContext",
+		"log/slog.WarnContext", "(*log/slog.Logger).WarnContext",
+		"log/slog.ErrorContext", "(*log/slog.Logger).ErrorContext":
+		return 2, true
+	case "log/slog.Log", "(*log/slog.Logger).Log":
+		return 3, true
+	case "log/slog.With", "(*log/slog.Logger).With":
+		return 0, true
+	}
+	return 0, false
+}
+
+// checkKeyValues checks the key/value pairs passed to fn starting at the
+// argument start. Strongly typed fields (zap.Field and slog.Attr) may appear
+// between the pairs and are checked by their constructors.
+func checkKeyValues(pass *analysis.Pass, call *ast.CallExpr, fn *types.Func, start int, keys *keyUsage, sensitive *sensitiveRules, ignoredFiles map[string]struct{}) {
+	if call.Ellipsis.IsValid() || len(call.Args) <= start {
+		// The pairs are passed as a slice, nothing to check statically.
+		return
+	}
+
+	label := apiLabel(fn)
+	ignored := func(expr ast.Expr) bool {
+		pos := pass.Fset.Position(expr.Pos())
+		if _, ok := ignoredFiles[pos.Filename]; ok {
+			return true
+		}
+		return hasIgnoreDirective(pass, expr.Pos())
+	}
+
+	args := call.Args[start:]
+	for i := 0; i < len(args); i++ {
+		key := args[i]
+		if isStandaloneField(pass.TypesInfo.TypeOf(key)) {
+			continue
+		}
+
+		if i+1 == len(args) {
+			if !ignored(key) {
+				pass.Reportf(key.Pos(), "%s key/value list has an odd number of elements, the last key has no value", label)
+			}
+			return
+		}
+		// Skip the value.
+		i++
+		value := args[i]
+
+		typ := pass.TypesInfo.TypeOf(key)
+		if typ == nil {
+			continue
+		}
+		if !isStringType(typ) {
+			// Interfaces may hold a string at runtime.
+			if !types.IsInterface(typ) && !ignored(key) {
+				pass.Reportf(key.Pos(), "%s key must be a string, not %s", label, typ.String())
+			}
+			continue
+		}
+
+		checkFieldKey(pass, key, label, ignoredFiles)
+		keys.collect(pass, key, label, ignoredFiles)
+		// Values are encoded with reflection, like zap.Any.
+		sensitive.checkSensitive(pass, key, value, label, true, ignoredFiles)
+	}
+}
+
+// isStandaloneField checks if typ is a strongly typed field that doesn't
+// need a key.
+func isStandaloneField(typ types.Type) bool {
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	switch named.Obj().Pkg().Path() + "." + named.Obj().Name() {
+	case "go.uber.org/zap.Field", "go.uber.org/zap/zapcore.Field", "log/slog.Attr":
+		return true
+	}
+	return false
+}
+
+// isStringType checks if the underlying type of typ is a string.
+func isStringType(typ types.Type) bool {
+	basic, ok := typ.Underlying().(*types.Basic)
+	return ok && basic.Info()&types.IsString != 0
+}
+
+// apiLabel returns how fn is named in diagnostics, e.g. zap.String,
+// zap.SugaredLogger.Infow or slog.Info.
+func apiLabel(fn *types.Func) string {
+	label := fn.Name()
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		t := types.Unalias(recv.Type())
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = types.Unalias(ptr.Elem())
+		}
+		if named, ok := t.(*types.Named); ok {
+			label = named.Obj().Name() + "." + label
+		}
+	}
+	if fn.Pkg() != nil {
+		label = fn.Pkg().Name() + "." + label
+	}
+	return label
+}
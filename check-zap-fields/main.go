@@ -6,6 +6,7 @@ package main
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
 	"go/types"
 	"regexp"
@@ -22,15 +23,16 @@ import (
 
 func main() { singlechecker.Main(Analyzer) }
 
-// Analyzer verifies that zap field names are valid.
+// Analyzer verifies that zap field names and logger messages are valid.
 // Valid field names must only contain lowercase ASCII letters, numbers, and underscores (only in the middle).
 var Analyzer = &analysis.Analyzer{
 	Name: "zapfields",
-	Doc:  "check that zap logger field names only contain lowercase ASCII letters, numbers, and underscores (only in the middle)",
+	Doc:  "check that zap logger field names only contain lowercase ASCII letters, numbers, and underscores (only in the middle), that they are used consistently and that logger messages are constant",
 	Run:  run,
 	Requires: []*analysis.Analyzer{
 		inspect.Analyzer,
 	},
+	FactTypes: []analysis.Fact{new(keysFact)},
 }
 
 func run(pass *analysis.Pass) (any, error) {
@@ -42,6 +44,9 @@ func run(pass *analysis.Pass) (any, error) {
 	// Build a map of files with ignore-file directives
 	ignoredFiles := buildIgnoredFilesMap(pass)
 
+	keys := newKeyUsage()
+	sensitive := newSensitiveRules()
+
 	inspect.Preorder(nodeFilter, func(n ast.Node) {
 		call := n.(*ast.CallExpr)
 
@@ -50,11 +55,31 @@ func run(pass *analysis.Pass) (any, error) {
 			return
 		}
 
-		if isZapFieldFunction(fn) {
-			checkZapFieldName(pass, call, fn, ignoredFiles)
+		if isZapFieldFunction(fn) || isSlogAttrFunction(fn) {
+			label := apiLabel(fn)
+			checkZapFieldName(pass, call, label, ignoredFiles)
+			if len(call.Args) > 0 {
+				keys.collect(pass, call.Args[0], label, ignoredFiles)
+
+				var value ast.Expr
+				if len(call.Args) > 1 && fn.FullName() != "log/slog.Group" {
+					value = call.Args[1]
+				}
+				sensitive.checkSensitive(pass, call.Args[0], value, label, isDeepField(fn), ignoredFiles)
+			}
+		}
+
+		if start, ok := keyValueStart(fn); ok {
+			checkKeyValues(pass, call, fn, start, keys, sensitive, ignoredFiles)
+		}
+
+		if isZapLoggerMethod(fn) {
+			checkLoggerMessage(pass, call, fn, ignoredFiles)
 		}
 	})
 
+	checkKeyConsistency(pass, keys)
+
 	return nil, nil
 }
 
@@ -130,24 +155,28 @@ func isZapFieldFunction(fn *types.Func) bool {
 //   - Not contain uppercase letters
 //   - Only contain ASCII letters, numbers, and underscores
 //   - Underscores must be in the middle (not at start or end)
-func checkZapFieldName(pass *analysis.Pass, call *ast.CallExpr, fn *types.Func, ignoredFiles map[string]struct{}) {
+func checkZapFieldName(pass *analysis.Pass, call *ast.CallExpr, label string, ignoredFiles map[string]struct{}) {
 	if len(call.Args) == 0 {
 		return
 	}
 
-	firstArg := call.Args[0]
+	checkFieldKey(pass, call.Args[0], label, ignoredFiles)
+}
 
-	// Check if it's a basic string literal
-	if lit, ok := firstArg.(*ast.BasicLit); ok {
-		if lit.Kind == token.STRING {
-			// Check if this line should be ignored
-			pos := pass.Fset.Position(lit.Pos())
-			if _, ok := ignoredFiles[pos.Filename]; ok {
-				return
-			}
+// checkFieldKey checks if key is a valid field name when it's a basic string
+// literal or a string constant.
+func checkFieldKey(pass *analysis.Pass, key ast.Expr, label string, ignoredFiles map[string]struct{}) {
+	// Check if this line should be ignored
+	pos := pass.Fset.Position(key.Pos())
+	if _, ok := ignoredFiles[pos.Filename]; ok {
+		return
+	}
 
-			checkNameLiteral(pass, lit, fn)
-		}
+	switch key := key.(type) {
+	case *ast.BasicLit:
+		checkNameLiteral(pass, key, label)
+	case *ast.Ident, *ast.SelectorExpr:
+		checkNameConstant(pass, key, label)
 	}
 }
 
@@ -157,7 +186,7 @@ func checkZapFieldName(pass *analysis.Pass, call *ast.CallExpr, fn *types.Func,
 // When possible it suggests an auto-fix.
 //
 // It only acts only if name is basic literal string.
-func checkNameLiteral(pass *analysis.Pass, name *ast.BasicLit, zapFn *types.Func) {
+func checkNameLiteral(pass *analysis.Pass, name *ast.BasicLit, label string) {
 	if name.Kind != token.STRING {
 		return
 	}
@@ -168,26 +197,90 @@ func checkNameLiteral(pass *analysis.Pass, name *ast.BasicLit, zapFn *types.Func
 		panic("BUG: the `strconv.Unquote` should have received an `ast.BasicLit.Value` of `kind == STRING`, so the value must have been quoted")
 	}
 
+	checkName(pass, name.Pos(), nval, label, name)
+}
+
+// checkNameConstant is like checkNameLiteral for names that come from a string
+// constant.
+//
+// The suggested auto-fix edits the literal in the constant's declaration, so it's
+// only possible when the constant is declared with a literal in the same package.
+func checkNameConstant(pass *analysis.Pass, name ast.Expr, label string) {
+	ident, ok := name.(*ast.Ident)
+	if sel, isSel := name.(*ast.SelectorExpr); isSel {
+		ident, ok = sel.Sel, true
+	}
+	if !ok {
+		return
+	}
+
+	obj, ok := pass.TypesInfo.ObjectOf(ident).(*types.Const)
+	if !ok || obj.Val().Kind() != constant.String {
+		return
+	}
+
+	checkName(pass, name.Pos(), constant.StringVal(obj.Val()), label, constantLiteral(pass, obj))
+}
+
+// constantLiteral returns the string literal that obj is declared with, or nil
+// when the declaration is not in the package or not a single literal.
+func constantLiteral(pass *analysis.Pass, obj *types.Const) *ast.BasicLit {
+	if obj.Pkg() != pass.Pkg {
+		return nil
+	}
+
+	for _, file := range pass.Files {
+		if obj.Pos() < file.FileStart || file.FileEnd <= obj.Pos() {
+			continue
+		}
+
+		var found *ast.BasicLit
+		ast.Inspect(file, func(n ast.Node) bool {
+			spec, ok := n.(*ast.ValueSpec)
+			if !ok {
+				return found == nil
+			}
+			for i, name := range spec.Names {
+				if name.Pos() != obj.Pos() || i >= len(spec.Values) {
+					continue
+				}
+				if lit, ok := spec.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					found = lit
+				}
+			}
+			return false
+		})
+		return found
+	}
+
+	return nil
+}
+
+// checkName reports the name value found at pos when it doesn't match the
+// ^[a-z0-9]+(_[a-z0-9]+)*$ regular expression.
+//
+// It suggests an auto-fix rewriting fixLit when it's not nil.
+func checkName(pass *analysis.Pass, pos token.Pos, nval, label string, fixLit *ast.BasicLit) {
 	sanitized, valid := sanitizeString(nval)
 	if valid {
 		return
 	}
 
-	if hasIgnoreDirective(pass, name.Pos()) {
+	if hasIgnoreDirective(pass, pos) {
 		// Don't do anything because this field's name is ignored.
 		return
 	}
 
-	if sanitized == "" {
+	if sanitized == "" || fixLit == nil {
 		if nval == "" {
 			nval = "<empty>"
 		}
 
 		pass.Report(analysis.Diagnostic{
 			Message: fmt.Sprintf(
-				"zap.%s field name %s doesn't match %s regular expression (NOT auto-fixable)",
-				zapFn.Name(), nval, rxValidFieldName.String()),
-			Pos: name.Pos(),
+				"%s field name %s doesn't match %s regular expression (NOT auto-fixable)",
+				label, nval, rxValidFieldName.String()),
+			Pos: pos,
 		})
 		return
 	}
@@ -199,15 +292,15 @@ func checkNameLiteral(pass *analysis.Pass, name *ast.BasicLit, zapFn *types.Func
 
 	pass.Report(analysis.Diagnostic{
 		Message: fmt.Sprintf(
-			"zap.%s field name %s doesn't match %s regular expression (auto-fixable)",
-			zapFn.Name(), nval, rxValidFieldName.String()),
-		Pos: name.Pos(),
+			"%s field name %s doesn't match %s regular expression (auto-fixable)",
+			label, nval, rxValidFieldName.String()),
+		Pos: pos,
 		SuggestedFixes: []analysis.SuggestedFix{
 			{
 				Message: "Replace unsupported chars by underscores and convert from camelCase to snake_case",
 				TextEdits: []analysis.TextEdit{
 					// Add one to start and subtract one to end to keep the double quote of the string literal.
-					{Pos: name.Pos() + 1, End: name.End() - 1, NewText: []byte(sanitized)},
+					{Pos: fixLit.Pos() + 1, End: fixLit.End() - 1, NewText: []byte(sanitized)},
 				},
 			},
 		},
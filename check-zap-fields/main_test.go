@@ -3,7 +3,16 @@
 
 package main
 
-import "testing"
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func Test(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.Run(t, testdata, Analyzer, "a", "keys", "kv", "sensitive")
+}
 
 func TestSanitizeString(t *testing.T) {
 	// The tests below are written based solely on the function's documentation comment
@@ -60,3 +69,35 @@ func TestSanitizeString(t *testing.T) {
 		})
 	}
 }
+
+func TestSuggestedFixes(t *testing.T) {
+	testdata := analysistest.TestData()
+	analysistest.RunWithSuggestedFixes(t, testdata, Analyzer, "fix")
+}
+
+func TestSimilarKeys(t *testing.T) {
+	for _, test := range []struct {
+		a, b    string
+		similar bool
+	}{
+		{"node_id", "nodeid", true},
+		{"node_id", "node", true},
+		{"segments_count", "segment_count", true},
+		{"bucket_name", "bucketname", true},
+		{"piece_size", "piece_sise", true},
+		{"expired", "expires", false},
+		{"limit", "limits", false},
+		{"offset", "offsets", false},
+		{"expired_at", "expires_at", false},
+		{"upload_created", "upload_creating", false},
+		{"node", "nodes", false},
+		{"bucket", "object", false},
+	} {
+		if got := similarKeys(test.a, test.b); got != test.similar {
+			t.Errorf("similarKeys(%q, %q) = %v, want %v", test.a, test.b, got, test.similar)
+		}
+		if got := similarKeys(test.b, test.a); got != test.similar {
+			t.Errorf("similarKeys(%q, %q) = %v, want %v", test.b, test.a, got, test.similar)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// isZapLoggerMethod checks if the function is a zap.Logger method that logs a message.
+func isZapLoggerMethod(fn *types.Func) bool {
+	switch fn.FullName() {
+	case "(*go.uber.org/zap.Logger).Debug",
+		"(*go.uber.org/zap.Logger).Info",
+		"(*go.uber.org/zap.Logger).Warn",
+		"(*go.uber.org/zap.Logger).Error",
+		"(*go.uber.org/zap.Logger).DPanic",
+		"(*go.uber.org/zap.Logger).Panic",
+		"(*go.uber.org/zap.Logger).Fatal":
+		return true
+	}
+	return false
+}
+
+// checkLoggerMessage checks that the first argument (message) of a logger method:
+//   - Is not built with fmt.Sprintf
+//   - Is a constant string
+//   - Does not end with a period (an ellipsis is fine)
+func checkLoggerMessage(pass *analysis.Pass, call *ast.CallExpr, fn *types.Func, ignoredFiles map[string]struct{}) {
+	if len(call.Args) == 0 {
+		return
+	}
+
+	msg := call.Args[0]
+
+	pos := pass.Fset.Position(msg.Pos())
+	if _, ok := ignoredFiles[pos.Filename]; ok {
+		return
+	}
+	if hasIgnoreDirective(pass, msg.Pos()) {
+		return
+	}
+
+	if inner, ok := ast.Unparen(msg).(*ast.CallExpr); ok {
+		if callee := typeutil.StaticCallee(pass.TypesInfo, inner); callee != nil && callee.FullName() == "fmt.Sprintf" {
+			pass.Reportf(msg.Pos(), "zap.Logger.%s message must not be built with fmt.Sprintf, use fields instead", fn.Name())
+			return
+		}
+	}
+
+	tv, ok := pass.TypesInfo.Types[msg]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		pass.Reportf(msg.Pos(), "zap.Logger.%s message must be a constant string, use fields for variable data", fn.Name())
+		return
+	}
+
+	text := constant.StringVal(tv.Value)
+	if !strings.HasSuffix(text, ".") || strings.HasSuffix(text, "...") {
+		return
+	}
+
+	diag := analysis.Diagnostic{
+		Message: fmt.Sprintf("zap.Logger.%s message %q must not end with a period", fn.Name(), text),
+		Pos:     msg.Pos(),
+	}
+	// Only literals can be fixed in place, the period of a constant may be
+	// anywhere in its declaration.
+	if lit, ok := msg.(*ast.BasicLit); ok && lit.Kind == token.STRING && len(lit.Value) >= 3 && lit.Value[len(lit.Value)-2] == '.' {
+		diag.SuggestedFixes = []analysis.SuggestedFix{
+			{
+				Message: "Remove the trailing period",
+				TextEdits: []analysis.TextEdit{
+					// Keep the closing quote of the string literal.
+					{Pos: lit.End() - 2, End: lit.End() - 1},
+				},
+			},
+		}
+	}
+	pass.Report(diag)
+}
@@ -0,0 +1,200 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+var (
+	sensitiveNames string
+	sensitiveTypes string
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&sensitiveNames, "sensitive-names",
+		"password,secret,token,api_key,access_grant,serialized_key",
+		"comma-separated list of field name words that indicate sensitive data")
+	Analyzer.Flags.StringVar(&sensitiveTypes, "sensitive-types",
+		"storj.io/common/macaroon.APIKey,storj.io/common/grant.Access,storj.io/uplink.Access",
+		"comma-separated list of types, as pkgpath.Name, whose values must not be logged")
+}
+
+// sensitiveRules are the parsed -sensitive-names and -sensitive-types flags.
+type sensitiveRules struct {
+	names []string
+	types map[string]bool
+}
+
+func newSensitiveRules() *sensitiveRules {
+	rules := &sensitiveRules{types: map[string]bool{}}
+	for _, name := range strings.Split(sensitiveNames, ",") {
+		name, _ = sanitizeString(strings.TrimSpace(name))
+		if name != "" {
+			rules.names = append(rules.names, name)
+		}
+	}
+	for _, name := range strings.Split(sensitiveTypes, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			rules.types[name] = true
+		}
+	}
+	return rules
+}
+
+// isDeepField checks if the field function encodes its value with reflection,
+// so that the fields of a struct value end up in the log.
+func isDeepField(fn *types.Func) bool {
+	switch fn.FullName() {
+	case "go.uber.org/zap.Any", "go.uber.org/zap.Reflect", "log/slog.Any":
+		return true
+	}
+	return false
+}
+
+// checkSensitive reports logging of likely secrets: field names matching
+// the sensitive names and values of sensitive types. When deep is true, the
+// value is encoded with reflection and the fields of structs are checked too.
+func (rules *sensitiveRules) checkSensitive(pass *analysis.Pass, key, value ast.Expr, label string, deep bool, ignoredFiles map[string]struct{}) {
+	ignored := func(expr ast.Expr) bool {
+		pos := pass.Fset.Position(expr.Pos())
+		if _, ok := ignoredFiles[pos.Filename]; ok {
+			return true
+		}
+		return hasIgnoreDirective(pass, expr.Pos())
+	}
+
+	if key != nil {
+		if tv, ok := pass.TypesInfo.Types[key]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+			name := constant.StringVal(tv.Value)
+			if word := rules.sensitiveName(name); word != "" && !ignored(key) {
+				pass.Reportf(key.Pos(), "%s field name %s looks like sensitive data (%s), do not log secrets", label, name, word)
+				return
+			}
+		}
+	}
+
+	if value == nil {
+		return
+	}
+	typ := pass.TypesInfo.TypeOf(value)
+	if typ == nil {
+		return
+	}
+
+	if name := rules.sensitiveType(typ); name != "" {
+		if !ignored(value) {
+			pass.Reportf(value.Pos(), "%s logs a value of type %s, which contains credentials", label, name)
+		}
+		return
+	}
+
+	if deep {
+		if path, name := rules.sensitiveStructField(typ, map[types.Type]bool{}); path != "" && !ignored(value) {
+			pass.Reportf(value.Pos(), "%s logs %s, whose field %s contains credentials (%s)", label, typ.String(), path, name)
+		}
+	}
+}
+
+// sensitiveName returns the sensitive word the field name ends with, if any.
+// Words are matched between underscores after sanitizing, so apiKey and
+// user_api_key both match api_key, but tokenizer doesn't match token. The
+// word must be the last one, optionally in plural, since the last word names
+// what is logged: auth_tokens matches token, but token_count doesn't.
+func (rules *sensitiveRules) sensitiveName(name string) string {
+	sanitized, _ := sanitizeString(name)
+	sanitized = "_" + sanitized
+	for _, word := range rules.names {
+		if strings.HasSuffix(sanitized, "_"+word) || strings.HasSuffix(sanitized, "_"+word+"s") {
+			return word
+		}
+	}
+	return ""
+}
+
+// sensitiveType returns the name of typ, dereferencing pointers, when it's
+// one of the sensitive types.
+func (rules *sensitiveRules) sensitiveType(typ types.Type) string {
+	for {
+		ptr, ok := types.Unalias(typ).(*types.Pointer)
+		if !ok {
+			break
+		}
+		typ = ptr.Elem()
+	}
+
+	named, ok := types.Unalias(typ).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	name := named.Obj().Pkg().Path() + "." + named.Obj().Name()
+	if rules.types[name] {
+		return name
+	}
+	return ""
+}
+
+// sensitiveStructField finds a field of the struct typ that is encoded when
+// logging it with reflection and that is sensitive by name or by type. It
+// returns the path to the field and the reason.
+func (rules *sensitiveRules) sensitiveStructField(typ types.Type, seen map[types.Type]bool) (path, reason string) {
+	for {
+		switch t := types.Unalias(typ).(type) {
+		case *types.Pointer:
+			typ = t.Elem()
+			continue
+		case *types.Slice:
+			typ = t.Elem()
+			continue
+		case *types.Array:
+			typ = t.Elem()
+			continue
+		case *types.Map:
+			typ = t.Elem()
+			continue
+		}
+		break
+	}
+
+	if seen[typ] {
+		return "", ""
+	}
+	seen[typ] = true
+
+	st, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		return "", ""
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i)).Get("json")
+		if !field.Exported() || tag == "-" {
+			// Not encoded by reflection.
+			continue
+		}
+
+		if name := rules.sensitiveType(field.Type()); name != "" {
+			return field.Name(), name
+		}
+		fieldName, _, _ := strings.Cut(tag, ",")
+		if fieldName == "" {
+			fieldName = field.Name()
+		}
+		if word := rules.sensitiveName(fieldName); word != "" {
+			return field.Name(), word
+		}
+		if sub, reason := rules.sensitiveStructField(field.Type(), seen); sub != "" {
+			return field.Name() + "." + sub, reason
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,30 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package a
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+const constMessage = "constant message"
+
+func fields(log *zap.Logger, id string) {
+	log.Info("valid", zap.String("User ID", id))   // want `zap.String field name User ID doesn't match \^\[a-z0-9\]\+\(_\[a-z0-9\]\+\)\*\$ regular expression \(auto-fixable\)`
+	log.Info("valid", zap.Int("Status-Code", 500)) // want `zap.Int field name Status-Code doesn't match .* regular expression \(auto-fixable\)`
+	log.Info("valid", zap.String("", id))          // want `zap.String field name <empty> doesn't match .* regular expression \(NOT auto-fixable\)`
+	log.Info("valid", zap.String("Bad Name", id))  //zapfields:ignore
+}
+
+func messages(log *zap.Logger, id string, err error) {
+	log.Info("starting")
+	log.Info(constMessage)
+	log.Warn("retrying...")
+	log.Debug(fmt.Sprintf("processing %s", id)) // want `zap.Logger.Debug message must not be built with fmt.Sprintf, use fields instead`
+	log.Error("failed: " + err.Error())         // want `zap.Logger.Error message must be a constant string, use fields for variable data`
+	log.Info(id)                                // want `zap.Logger.Info message must be a constant string, use fields for variable data`
+	log.Warn("disk almost full.")               // want `zap.Logger.Warn message "disk almost full." must not end with a period`
+	log.Info(id)                                //zapfields:ignore
+}
@@ -0,0 +1,19 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package fix
+
+import "go.uber.org/zap"
+
+const (
+	keyNode  = "NodeID"
+	keyBytes = "bytes-read"
+)
+
+func fix(log *zap.Logger, sugar *zap.SugaredLogger, id string, n int) {
+	log.Info("valid", zap.String("User ID", id))   // want `zap.String field name User ID doesn't match .* regular expression \(auto-fixable\)`
+	log.Info("valid", zap.Int("Status-Code", 500)) // want `zap.Int field name Status-Code doesn't match .* regular expression \(auto-fixable\)`
+	log.Info("valid", zap.String(keyNode, id))     // want `zap.String field name NodeID doesn't match .* regular expression \(auto-fixable\)`
+	sugar.Infow("valid", keyNode, id, keyBytes, n) // want `zap.SugaredLogger.Infow field name NodeID doesn't match .* regular expression \(auto-fixable\)` `zap.SugaredLogger.Infow field name bytes-read doesn't match .* regular expression \(auto-fixable\)`
+	log.Warn("done.")                              // want `zap.Logger.Warn message "done." must not end with a period`
+}
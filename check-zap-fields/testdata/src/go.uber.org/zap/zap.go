@@ -0,0 +1,61 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package zap is a minimal stub of go.uber.org/zap for tests.
+package zap
+
+import "fmt"
+
+// Field is a logging field.
+type Field struct{}
+
+// String constructs a string field.
+func String(key string, val string) Field { return Field{} }
+
+// Int constructs an int field.
+func Int(key string, val int) Field { return Field{} }
+
+// Int64 constructs an int64 field.
+func Int64(key string, val int64) Field { return Field{} }
+
+// Stringer constructs a field with the value's String.
+func Stringer(key string, val fmt.Stringer) Field { return Field{} }
+
+// Any constructs a field of any type.
+func Any(key string, val interface{}) Field { return Field{} }
+
+// Reflect constructs a field using reflection.
+func Reflect(key string, val interface{}) Field { return Field{} }
+
+// Error constructs an error field.
+func Error(err error) Field { return Field{} }
+
+// Logger is a structured logger.
+type Logger struct{}
+
+// Debug logs at debug level.
+func (log *Logger) Debug(msg string, fields ...Field) {}
+
+// Info logs at info level.
+func (log *Logger) Info(msg string, fields ...Field) {}
+
+// Warn logs at warn level.
+func (log *Logger) Warn(msg string, fields ...Field) {}
+
+// Error logs at error level.
+func (log *Logger) Error(msg string, fields ...Field) {}
+
+// Sugar returns a sugared logger.
+func (log *Logger) Sugar() *SugaredLogger { return &SugaredLogger{} }
+
+// SugaredLogger is a loosely typed logger.
+type SugaredLogger struct{}
+
+// Infof logs a formatted message at info level.
+func (s *SugaredLogger) Infof(template string, args ...interface{}) {}
+
+// Infow logs a message with key/value pairs at info level.
+func (s *SugaredLogger) Infow(msg string, keysAndValues ...interface{}) {}
+
+// Errorw logs a message with key/value pairs at error level.
+func (s *SugaredLogger) Errorw(msg string, keysAndValues ...interface{}) {}
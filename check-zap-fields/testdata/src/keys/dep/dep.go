@@ -0,0 +1,14 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package dep
+
+import "go.uber.org/zap"
+
+// Log logs with the canonical field names.
+func Log(log *zap.Logger, id string) {
+	log.Info("node", zap.String("node_id", id))
+	log.Info("node", zap.String("node_id", id), zap.Int("segment_count", 1))
+	log.Info("page", zap.Int("limits", 1), zap.Int("offsets", 1), zap.Int("expires", 1))
+	log.Info("page", zap.Int("limits", 1), zap.Int("offsets", 1), zap.Int("expires", 1))
+}
@@ -0,0 +1,21 @@
+// Copyright (C) 2026 Storj Labs, Inc. // want package:"expired:1 limit:1 node:1 nodeid:1 offset:1 piece_size:2 segment_count:1 segments_count:1"
+// See LICENSE for copying information.
+
+package keys
+
+import (
+	"go.uber.org/zap"
+
+	"keys/dep"
+)
+
+func logAll(log *zap.Logger, id string) {
+	dep.Log(log, id)
+	log.Info("node", zap.String("nodeid", id))      // want `zap.String field name nodeid is inconsistent with node_id used elsewhere in the module`
+	log.Info("node", zap.String("node", id))        // want `zap.String field name node is inconsistent with node_id used elsewhere in the module`
+	log.Info("count", zap.Int("segments_count", 1)) // want `zap.Int field name segments_count is inconsistent with segment_count used elsewhere in the module`
+	log.Info("count", zap.Int("segment_count", 1))
+	log.Info("size", zap.Int("piece_size", 1), zap.Int("piece_size", 2))
+	// plurals and tenses name different things
+	log.Info("page", zap.Int("limit", 1), zap.Int("offset", 1), zap.Int("expired", 1))
+}
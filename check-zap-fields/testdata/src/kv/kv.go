@@ -0,0 +1,32 @@
+// Copyright (C) 2026 Storj Labs, Inc. // want package:"count:1 id:1 user_id:2"
+// See LICENSE for copying information.
+
+package kv
+
+import (
+	"context"
+	"log/slog"
+
+	"go.uber.org/zap"
+)
+
+const userKey = "user_id"
+
+func sugared(log *zap.SugaredLogger, id string, key string, keys []interface{}) {
+	log.Infow("valid", "user_id", id, userKey, id, key, id)
+	log.Infow("valid", "UserID", id)                        // want `zap.SugaredLogger.Infow field name UserID doesn't match .* regular expression \(auto-fixable\)`
+	log.Errorw("valid", zap.String("Status-Code", id), "a") // want `zap.String field name Status-Code doesn't match .* regular expression \(auto-fixable\)` `zap.SugaredLogger.Errorw key/value list has an odd number of elements, the last key has no value`
+	log.Infow("valid", 42, id)                              // want `zap.SugaredLogger.Infow key must be a string, not int`
+	log.Infow("valid", keys...)
+	log.Infow("valid", "Bad Key", id) //zapfields:ignore
+}
+
+func slogs(ctx context.Context, log *slog.Logger, id string, v any) {
+	slog.Info("valid", "user_id", id, slog.Int("count", 1))
+	slog.Info("valid", "UserID", id)                     // want `slog.Info field name UserID doesn't match .* regular expression \(auto-fixable\)`
+	slog.InfoContext(ctx, "valid", "Request ID", id)     // want `slog.InfoContext field name Request ID doesn't match .* regular expression \(auto-fixable\)`
+	log.Warn("valid", slog.String("StatusCode", id))     // want `slog.String field name StatusCode doesn't match .* regular expression \(auto-fixable\)`
+	log.Log(ctx, slog.LevelInfo, "valid", "user_id")     // want `slog.Logger.Log key/value list has an odd number of elements, the last key has no value`
+	log.With("Node", id).Info("valid", v, id)            // want `slog.Logger.With field name Node doesn't match .* regular expression \(auto-fixable\)`
+	slog.Error("valid", slog.Group("Request", "id", id)) // want `slog.Group field name Request doesn't match .* regular expression \(auto-fixable\)`
+}
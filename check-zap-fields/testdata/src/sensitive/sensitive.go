@@ -0,0 +1,53 @@
+// Copyright (C) 2026 Storj Labs, Inc. // want package:"auth_tokens:1 bucket:2 config:2 password:2 password_length:1 project_id:1 request:1 secret:1 token:1 token_count:1 tokenizer:1 tokens_used:1 user_api_key:1"
+// See LICENSE for copying information.
+
+package sensitive
+
+import (
+	"log/slog"
+
+	"go.uber.org/zap"
+
+	"storj.io/common/grant"
+	"storj.io/common/macaroon"
+)
+
+type request struct {
+	Bucket string
+	Access *grant.Access
+}
+
+type credentials struct {
+	User     string
+	Password string
+}
+
+type config struct {
+	Name  string
+	Creds credentials
+	key   *macaroon.APIKey
+	Skip  *macaroon.APIKey `json:"-"`
+}
+
+func names(log *zap.Logger, sugar *zap.SugaredLogger, value string) {
+	log.Info("login", zap.String("password", value))     // want `zap.String field name password looks like sensitive data \(password\), do not log secrets`
+	log.Info("login", zap.String("user_api_key", value)) // want `zap.String field name user_api_key looks like sensitive data \(api_key\), do not log secrets`
+	log.Info("login", zap.String("accessGrant", value))  // want `zap.String field name accessGrant looks like sensitive data \(access_grant\), do not log secrets` `zap.String field name accessGrant doesn't match .* regular expression \(auto-fixable\)`
+	sugar.Infow("login", "secret", value)                // want `zap.SugaredLogger.Infow field name secret looks like sensitive data \(secret\), do not log secrets`
+	slog.Info("login", "token", value)                   // want `slog.Info field name token looks like sensitive data \(token\), do not log secrets`
+	log.Info("login", zap.String("auth_tokens", value))  // want `zap.String field name auth_tokens looks like sensitive data \(token\), do not log secrets`
+	log.Info("parse", zap.String("tokenizer", value))    // different word
+	log.Info("usage", zap.Int("token_count", 1))         // counts tokens
+	log.Info("usage", zap.Int("tokens_used", 1))         // counts tokens
+	log.Info("login", zap.Int("password_length", 1))     // describes the password
+	log.Info("login", zap.String("password", value))     //zapfields:ignore
+}
+
+func values(log *zap.Logger, key *macaroon.APIKey, access grant.Access, req request, cfg config) {
+	log.Info("key", zap.Any("project_id", key)) // want `zap.Any logs a value of type storj.io/common/macaroon.APIKey, which contains credentials`
+	log.Info("key", zap.Stringer("bucket", nil))
+	slog.Info("access", "bucket", access)            // want `slog.Info logs a value of type storj.io/common/grant.Access, which contains credentials`
+	log.Info("request", zap.Reflect("request", req)) // want `zap.Reflect logs sensitive.request, whose field Access contains credentials \(storj.io/common/grant.Access\)`
+	log.Info("config", zap.Any("config", cfg))       // want `zap.Any logs sensitive.config, whose field Creds.Password contains credentials \(password\)`
+	log.Info("config", zap.Any("config", cfg))       //zapfields:ignore
+}
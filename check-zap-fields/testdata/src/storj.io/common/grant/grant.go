@@ -0,0 +1,13 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package grant is a minimal stub of storj.io/common/grant for tests.
+package grant
+
+import "storj.io/common/macaroon"
+
+// Access is an access grant.
+type Access struct {
+	SatelliteAddress string
+	APIKey           *macaroon.APIKey
+}
@@ -0,0 +1,11 @@
+// Copyright (C) 2026 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+// Package macaroon is a minimal stub of storj.io/common/macaroon for tests.
+package macaroon
+
+// APIKey is a macaroon based API key.
+type APIKey struct{ secret []byte }
+
+// Serialize serializes the API key.
+func (a *APIKey) Serialize() string { return "" }
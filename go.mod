@@ -8,6 +8,7 @@ require (
 	github.com/zeebo/errs v1.4.0
 	golang.org/x/mod v0.33.0
 	golang.org/x/tools v0.42.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require golang.org/x/sync v0.19.0 // indirect